@@ -0,0 +1,51 @@
+package mem
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Allocator hands out raw memory blocks. *Arena satisfies it, so libraries can depend on the
+// interface and accept either an arena or a HeapAllocator (e.g. in tests).
+type Allocator interface {
+	AllocateBytes(size uintptr) ([]byte, error)
+}
+
+var (
+	_ Allocator = (*Arena)(nil)
+	_ Allocator = HeapAllocator{}
+)
+
+// HeapAllocator is an Allocator backed by ordinary Go heap allocations.
+type HeapAllocator struct{}
+
+// AllocateBytes returns a new zeroed heap slice of size bytes. It never fails.
+func (HeapAllocator) AllocateBytes(size uintptr) ([]byte, error) {
+	return make([]byte, size), nil
+}
+
+// AllocateStructVia allocates a zeroed T from alloc. It returns an error if the block handed
+// out by alloc is too small or not aligned for T.
+func AllocateStructVia[T any](alloc Allocator) (*T, error) {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if size == 0 {
+		return new(T), nil
+	}
+
+	block, err := alloc.AllocateBytes(size)
+	if err != nil {
+		return nil, err
+	}
+	if uintptr(len(block)) < size {
+		return nil, fmt.Errorf("allocator returned %d bytes, need %d for %T", len(block), size, zero)
+	}
+	data := unsafe.Pointer(unsafe.SliceData(block))
+	if uintptr(data)%unsafe.Alignof(zero) != 0 {
+		return nil, fmt.Errorf("allocator returned memory not aligned to %d for %T", unsafe.Alignof(zero), zero)
+	}
+
+	ptr := (*T)(data)
+	*ptr = zero
+	return ptr, nil
+}
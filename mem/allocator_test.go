@@ -0,0 +1,91 @@
+package mem
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+// shortAllocator returns blocks one byte smaller than requested.
+type shortAllocator struct{}
+
+func (shortAllocator) AllocateBytes(size uintptr) ([]byte, error) {
+	return make([]byte, size-1), nil
+}
+
+func TestAllocateStructVia(t *testing.T) {
+	type point struct {
+		X, Y int64
+	}
+
+	allocators := map[string]func() Allocator{
+		"arena": func() Allocator { return NewArenaWithSizeUnsafe(1024) },
+		"heap":  func() Allocator { return HeapAllocator{} },
+	}
+
+	for name, newAllocator := range allocators {
+		t.Run(name+" returns usable zeroed structs", func(t *testing.T) {
+			alloc := newAllocator()
+
+			first, err := AllocateStructVia[point](alloc)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			second, _ := AllocateStructVia[point](alloc)
+			first.X = 1
+			second.Y = 2
+
+			if *first != (point{X: 1}) || *second != (point{Y: 2}) {
+				t.Errorf("expected independent structs, got %+v and %+v", *first, *second)
+			}
+			if uintptr(unsafe.Pointer(first))%unsafe.Alignof(point{}) != 0 {
+				t.Error("expected aligned struct")
+			}
+		})
+	}
+
+	t.Run("arena allocations come from arena memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		p, _ := AllocateStructVia[point](arena)
+
+		address := uintptr(unsafe.Pointer(p))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Error("expected struct in arena memory")
+		}
+	})
+
+	t.Run("propagates allocator errors", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+
+		if _, err := AllocateStructVia[point](arena); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("rejects blocks that are too small", func(t *testing.T) {
+		if _, err := AllocateStructVia[point](shortAllocator{}); err == nil {
+			t.Error("expected error for a short block")
+		}
+	})
+}
+
+func TestHeapAllocator(t *testing.T) {
+	t.Run("returns zeroed blocks of the requested size", func(t *testing.T) {
+		var alloc Allocator = HeapAllocator{}
+
+		block, err := alloc.AllocateBytes(32)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(block) != 32 {
+			t.Errorf("expected 32 bytes, got %d", len(block))
+		}
+		for i, b := range block {
+			if b != 0 {
+				t.Fatalf("expected zeroed block, byte %d = %d", i, b)
+			}
+		}
+	})
+}
@@ -7,9 +7,29 @@ package mem
 
 import (
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"unsafe"
 )
 
+// ErrCapacityExceeded is returned when an allocation does not fit in the remaining arena memory.
+var ErrCapacityExceeded = errors.New("arena capacity exceeded: cannot allocate required memory")
+
+// ErrArenaTooSmall is returned by NewArena when the memory block cannot even fit the
+// padding needed to align its start to the cache line size.
+var ErrArenaTooSmall = errors.New("arena memory too small for cache line alignment")
+
+// ErrEmptyMemory is returned when an arena would be created over an empty memory block:
+// by NewArena, CreateChild with a zero size, or LoadArena for a header with zero capacity.
+var ErrEmptyMemory = errors.New("memory cannot be empty")
+
+// ErrInvalidSize is returned when a negative size or element count is requested.
+var ErrInvalidSize = errors.New("arena allocation size must not be negative")
+
+// ErrArenaCorrupted is returned by allocations when the exported Memory or Capacity fields
+// have been changed so they no longer describe the arena's backing memory.
+var ErrArenaCorrupted = errors.New("arena Memory or Capacity no longer match the backing memory")
+
 // Arena represents the Arena structure for memory management.
 // It acts as a bump-pointer allocator over a pre-allocated memory block.
 type Arena struct {
@@ -26,15 +46,73 @@ type Arena struct {
 	// with the race detector. This keeps the connection to the original allocation.
 	basePtr *byte
 
+	// length is the real length of the backing memory, used to detect a Capacity that
+	// was changed to exceed it.
+	length uintptr
+
 	// ArenaResetOffset is the boundary between Persistent and Ephemeral memory.
 	// This field is crucial for the O(1) frame reset mechanism described in the training module.
 	ArenaResetOffset uintptr
 
 	// CacheLineSize is the size of the cache line to use for alignment.
 	CacheLineSize uintptr
+
+	// defaultAlignment is the start and end alignment of Allocate blocks, CacheLineSize
+	// unless set with ArenaWithDefaultAlignment.
+	defaultAlignment uintptr
+
+	// topOffset is where the blocks carved by AllocateFromTop begin; Capacity when there are
+	// none. Allocations from the bottom may not grow past it.
+	topOffset uintptr
+
+	// dataStart is the initial NextAllocation: the padding that aligns the first allocation.
+	dataStart uintptr
+
+	// highWaterMark is the largest NextAllocation ever reached, preserved across resets.
+	highWaterMark uintptr
+
+	// allocationCount is the number of successful allocations over the arena's lifetime.
+	allocationCount uint64
+
+	// checkpoints is the stack of persistent checkpoints pushed with PushPersistentCheckpoint.
+	checkpoints []uintptr
+
+	// generation counts resets; see Generation.
+	generation uint64
+
+	// resets remembers the offset each reset rewound to, for use-after-reset checks in
+	// arenadebug builds. It is empty in regular builds.
+	resets resetHistory
+
+	// allocationLog records every allocation when enabled with ArenaWithAllocationLog.
+	allocationLog *offsetLog[AllocationRecord]
+
+	// allocationTags records AllocateTagged sizes when enabled with ArenaWithAllocationTags.
+	allocationTags *offsetLog[taggedAllocation]
+
+	// typedLayout records struct allocations when enabled with ArenaWithTypedLayout.
+	typedLayout *offsetLog[TypedRecord]
+
+	// threadSafe makes Allocate (and everything built on it) bump NextAllocation with
+	// atomic compare-and-swap so concurrent goroutines receive disjoint blocks.
+	threadSafe bool
+}
+
+// ArenaStats is a snapshot of arena usage, intended for tuning arena sizes.
+type ArenaStats struct {
+	// HighWaterMark is the peak NextAllocation, preserved across resets.
+	HighWaterMark uintptr
+	// AllocationCount is the total number of successful allocations.
+	AllocationCount uint64
 }
 type ArenaOptions struct {
 	CacheLineSize uintptr
+	// DefaultAlignment is the alignment of Allocate blocks; 0 means CacheLineSize.
+	DefaultAlignment uintptr
+	ThreadSafe       bool
+	AllocationLog    bool
+	AllocationTags   bool
+	TypedLayout      bool
 }
 
 type ArenaOption func(*ArenaOptions)
@@ -45,19 +123,38 @@ func ArenaWithCacheLineSize(size uintptr) ArenaOption {
 	}
 }
 
+// ArenaWithDefaultAlignment makes Allocate (and everything built on it) start and end blocks on
+// alignment instead of the cache line size, e.g. 8 or 16 so raw blocks can be reinterpreted as
+// any type of that alignment with less padding. alignment must be a power of two.
+func ArenaWithDefaultAlignment(alignment uintptr) ArenaOption {
+	return func(o *ArenaOptions) {
+		o.DefaultAlignment = alignment
+	}
+}
+
+// ArenaWithThreadSafe makes concurrent Allocate and AllocateStruct calls safe without a mutex.
+// Resets and persistent-memory marking must still not race with allocations.
+func ArenaWithThreadSafe() ArenaOption {
+	return func(o *ArenaOptions) {
+		o.ThreadSafe = true
+	}
+}
+
 func defaultArenaOptions() ArenaOptions {
 	return ArenaOptions{
 		CacheLineSize: 64,
 	}
 }
 
+// NewArenaWithSize allocates a cache-line aligned memory block of size bytes, so the
+// whole block is usable without alignment padding.
 func NewArenaWithSize(size int) (*Arena, error) {
-	memory := make([]byte, size)
+	memory := alignedMemory(size, defaultArenaOptions().CacheLineSize)
 	return NewArena(memory)
 }
 
 func NewArenaWithSizeUnsafe(size int) *Arena {
-	memory := make([]byte, size)
+	memory := alignedMemory(size, defaultArenaOptions().CacheLineSize)
 	arena, err := NewArena(memory)
 	if err != nil {
 		panic(err)
@@ -65,7 +162,51 @@ func NewArenaWithSizeUnsafe(size int) *Arena {
 	return arena
 }
 
+// hugePageSize is the 2MB boundary used by NewArenaHugePageAligned.
+const hugePageSize = 2 << 20
+
+// NewArenaHugePageAligned allocates a memory block of size bytes whose start is aligned to a
+// 2MB boundary, so the kernel can back it with transparent huge pages where enabled. The
+// alignment is obtained by over-allocating and slicing, which works on every platform.
+func NewArenaHugePageAligned(size int) (*Arena, error) {
+	memory := alignedMemory(size, hugePageSize)
+	return NewArena(memory)
+}
+
+// SizeForStructs returns a memory size for NewArena that is guaranteed to fit n AllocateStruct[T]
+// calls with the default options: the packed structs plus worst-case padding for aligning the
+// arena start to the cache line size.
+func SizeForStructs[T any](n int32) uintptr {
+	var zero T
+	return uintptr(max(n, 0))*unsafe.Sizeof(zero) + defaultArenaOptions().CacheLineSize - 1
+}
+
+// SizeForAllocations returns a memory size for NewArena that is guaranteed to fit one Allocate
+// call per entry in sizes with the default options. Each block is rounded up to the cache
+// line size, plus worst-case padding for aligning the arena start.
+func SizeForAllocations(sizes ...uintptr) uintptr {
+	cacheLineSize := defaultArenaOptions().CacheLineSize
+	total := cacheLineSize - 1
+	for _, size := range sizes {
+		total += (size + cacheLineSize - 1) &^ (cacheLineSize - 1)
+	}
+	return total
+}
+
+// alignedMemory allocates size bytes whose first byte sits on an alignment boundary,
+// by over-allocating and slicing. alignment must be a power of two.
+func alignedMemory(size int, alignment uintptr) []byte {
+	if size <= 0 {
+		return nil
+	}
+	backing := make([]byte, size+int(alignment)-1)
+	padding := (alignment - (uintptr(unsafe.Pointer(&backing[0])) % alignment)) & (alignment - 1)
+	return backing[padding : padding+uintptr(size) : padding+uintptr(size)]
+}
+
 // NewArena initializes the Arena structure with a pre-allocated byte slice.
+// A nil slice is treated as empty. The first allocation starts at the first address
+// aligned to the cache line size; the bytes before it are never handed out.
 func NewArena(memory []byte, options ...ArenaOption) (*Arena, error) {
 	opts := defaultArenaOptions()
 	for _, option := range options {
@@ -73,18 +214,39 @@ func NewArena(memory []byte, options ...ArenaOption) (*Arena, error) {
 	}
 
 	if len(memory) == 0 {
-		return nil, errors.New("memory cannot be empty")
+		return nil, ErrEmptyMemory
+	}
+	if opts.CacheLineSize == 0 || opts.CacheLineSize&(opts.CacheLineSize-1) != 0 {
+		return nil, fmt.Errorf("cache line size must be a power of two, got %d", opts.CacheLineSize)
+	}
+	if opts.DefaultAlignment == 0 {
+		opts.DefaultAlignment = opts.CacheLineSize
+	}
+	if opts.DefaultAlignment&(opts.DefaultAlignment-1) != 0 {
+		return nil, fmt.Errorf("default alignment must be a power of two, got %d", opts.DefaultAlignment)
 	}
 
 	memStartPtr := uintptr(unsafe.Pointer(&memory[0]))
+	alignmentPadding := (opts.CacheLineSize - (memStartPtr % opts.CacheLineSize)) & (opts.CacheLineSize - 1)
+	if alignmentPadding >= uintptr(len(memory)) {
+		return nil, ErrArenaTooSmall
+	}
 
 	a := &Arena{
 		Memory:           memStartPtr,
 		basePtr:          &memory[0],
+		length:           uintptr(len(memory)),
 		Capacity:         uintptr(len(memory)),
-		NextAllocation:   0,
-		ArenaResetOffset: 0,
+		NextAllocation:   alignmentPadding,
+		ArenaResetOffset: alignmentPadding,
+		dataStart:        alignmentPadding,
+		topOffset:        uintptr(len(memory)),
 		CacheLineSize:    opts.CacheLineSize,
+		defaultAlignment: opts.DefaultAlignment,
+		threadSafe:       opts.ThreadSafe,
+		allocationLog:    newOffsetLog[AllocationRecord](opts.AllocationLog),
+		allocationTags:   newOffsetLog[taggedAllocation](opts.AllocationTags),
+		typedLayout:      newOffsetLog[TypedRecord](opts.TypedLayout),
 	}
 
 	return a, nil
@@ -92,15 +254,126 @@ func NewArena(memory []byte, options ...ArenaOption) (*Arena, error) {
 
 // Allocate attempts to allocate a block of memory of the given size from the arena.
 // It returns the address of the allocated memory and an error if the allocation fails.
+// Blocks start and end on addresses aligned to the cache line size, or to the alignment set
+// with ArenaWithDefaultAlignment. A zero size always succeeds: it returns the address where
+// the next allocation would begin (never past the end of the arena) with a nil error, and
+// neither advances NextAllocation nor counts in Stats.
 func (a *Arena) Allocate(size uintptr) (uintptr, error) {
-	nextAllocOffset := a.NextAllocation + ((a.CacheLineSize - ((a.NextAllocation + size) % a.CacheLineSize)) & (a.CacheLineSize - 1)) + size
-	if a.NextAllocation+size <= a.Capacity {
-		thisAllocationOffset := a.Memory + a.NextAllocation
-		a.NextAllocation = nextAllocOffset
-		return thisAllocationOffset, nil
-	} else {
-		return 0, errors.New("arena capacity exceeded: cannot allocate required memory")
+	return a.bump(size, a.defaultAlignment, a.defaultAlignment, AllocationKindBlock)
+}
+
+// AllocateFromTop carves size bytes from the high end of the arena downwards, so temporaries
+// can grow from the top while other data grows from the bottom, meeting in the middle. Blocks
+// start on addresses aligned like Allocate blocks. It returns ErrCapacityExceeded if the block
+// would reach below NextAllocation, and bottom allocations likewise fail rather than grow into
+// the top blocks. Every reset (ResetEphemeralMemory, Reset and ResetToCheckpoint) releases
+// all top blocks. Top blocks are not persisted by WriteTo, and AllocateFromTop must not race
+// with other allocations, even on a thread-safe arena. A zero size returns an empty slice
+// without allocating.
+func (a *Arena) AllocateFromTop(size uintptr) ([]byte, error) {
+	if err := a.validate(); err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	top := a.limit()
+	if size > top {
+		return nil, ErrCapacityExceeded
+	}
+	start := top - size
+	start -= (a.Memory + start) % a.defaultAlignment
+	if start < a.loadNextAllocation() || start > top {
+		return nil, ErrCapacityExceeded
+	}
+	a.topOffset = start
+	return unsafe.Slice((*byte)(a.pointerAt(a.Memory+start)), size), nil
+}
+
+// limit is the end of the region available to bottom allocations.
+func (a *Arena) limit() uintptr {
+	return min(a.topOffset, a.Capacity)
+}
+
+// AllocateAligned allocates size bytes starting at the first address aligned to alignment,
+// which must be a power of two. Unlike Allocate, the end of the block is not padded, so
+// back-to-back allocations of the same aligned size are packed without gaps.
+// A zero size behaves as in Allocate and does not advance NextAllocation.
+// When the block does not fit, the error wraps ErrCapacityExceeded and reports the requested
+// size, the alignment padding and the remaining bytes, since the padding alone can make a
+// block that would otherwise fit fail near the end of the arena.
+func (a *Arena) AllocateAligned(size uintptr, alignment uintptr) (uintptr, error) {
+	if alignment == 0 || alignment&(alignment-1) != 0 {
+		return 0, fmt.Errorf("alignment must be a power of two, got %d", alignment)
+	}
+	address, err := a.bump(size, alignment, 1, AllocationKindAligned)
+	if errors.Is(err, ErrCapacityExceeded) {
+		current := a.loadNextAllocation()
+		return 0, fmt.Errorf("%w: requested %d bytes plus %d bytes of alignment padding, %d bytes remaining",
+			ErrCapacityExceeded, size, a.paddingFor(current, alignment), a.Remaining())
+	}
+	return address, err
+}
+
+// bump reserves size bytes at the first offset whose address is aligned to alignment, then
+// rounds NextAllocation up so its address is aligned to endAlignment. kind is only used for
+// the allocation log.
+func (a *Arena) bump(size uintptr, alignment uintptr, endAlignment uintptr, kind AllocationKind) (uintptr, error) {
+	if err := a.validate(); err != nil {
+		return 0, err
+	}
+	for {
+		current := a.loadNextAllocation()
+		start := current + a.paddingFor(current, alignment)
+		if size == 0 {
+			return a.Memory + min(start, a.limit()), nil
+		}
+		// Compare against the remaining space rather than start+size, which can wrap
+		// around for very large sizes and bypass the check.
+		limit := a.limit()
+		if start > limit || size > limit-start {
+			return 0, ErrCapacityExceeded
+		}
+		end := start + size
+		next := end + a.paddingFor(end, endAlignment)
+		if a.swapNextAllocation(current, next) {
+			a.recordAllocation(next)
+			a.allocationLog.record(AllocationRecord{Size: size, Offset: start, Kind: kind})
+			return a.Memory + start, nil
+		}
+	}
+}
+
+// validate checks that Memory and Capacity still describe the backing memory, so a stray
+// write to the exported fields fails cleanly instead of corrupting memory outside the arena.
+func (a *Arena) validate() error {
+	if a.Memory != uintptr(unsafe.Pointer(a.basePtr)) || a.Capacity > a.length {
+		return ErrArenaCorrupted
+	}
+	return nil
+}
+
+// paddingFor returns the number of bytes needed to move offset to an address aligned to alignment.
+func (a *Arena) paddingFor(offset uintptr, alignment uintptr) uintptr {
+	return (alignment - ((a.Memory + offset) % alignment)) & (alignment - 1)
+}
+
+// loadNextAllocation reads NextAllocation, atomically for thread-safe arenas.
+func (a *Arena) loadNextAllocation() uintptr {
+	if a.threadSafe {
+		return atomic.LoadUintptr(&a.NextAllocation)
+	}
+	return a.NextAllocation
+}
+
+// swapNextAllocation moves NextAllocation from current to next. For thread-safe arenas
+// this is a compare-and-swap that fails if another goroutine allocated in between.
+func (a *Arena) swapNextAllocation(current uintptr, next uintptr) bool {
+	if a.threadSafe {
+		return atomic.CompareAndSwapUintptr(&a.NextAllocation, current, next)
 	}
+	a.NextAllocation = next
+	return true
 }
 
 func (a *Arena) Array_Allocate_Arena(capacity int32, itemSize uint32) (uintptr, error) {
@@ -108,6 +381,150 @@ func (a *Arena) Array_Allocate_Arena(capacity int32, itemSize uint32) (uintptr,
 	return a.Allocate(totalSizeBytes)
 }
 
+// recordAllocation updates the usage statistics after a successful allocation ending at next.
+func (a *Arena) recordAllocation(next uintptr) {
+	if a.threadSafe {
+		atomic.AddUint64(&a.allocationCount, 1)
+		for {
+			highWaterMark := atomic.LoadUintptr(&a.highWaterMark)
+			if next <= highWaterMark || atomic.CompareAndSwapUintptr(&a.highWaterMark, highWaterMark, next) {
+				return
+			}
+		}
+	}
+	a.allocationCount++
+	if next > a.highWaterMark {
+		a.highWaterMark = next
+	}
+}
+
+// Remaining returns the number of bytes between NextAllocation and the end of the arena (or the
+// lowest AllocateFromTop block), ignoring any alignment padding the next allocation would need.
+func (a *Arena) Remaining() uintptr {
+	current := a.loadNextAllocation()
+	if current >= a.limit() {
+		return 0
+	}
+	return a.limit() - current
+}
+
+// AlignedRemaining returns the largest size AllocateAligned(size, alignment) could currently
+// satisfy: Remaining minus the padding needed to reach alignment. It returns 0 if alignment is
+// not a power of two.
+func (a *Arena) AlignedRemaining(alignment uintptr) uintptr {
+	if alignment == 0 || alignment&(alignment-1) != 0 {
+		return 0
+	}
+	current := a.loadNextAllocation()
+	start := current + a.paddingFor(current, alignment)
+	if start >= a.limit() {
+		return 0
+	}
+	return a.limit() - start
+}
+
+// Stats returns the arena's usage statistics.
+func (a *Arena) Stats() ArenaStats {
+	if a.threadSafe {
+		return ArenaStats{
+			HighWaterMark:   atomic.LoadUintptr(&a.highWaterMark),
+			AllocationCount: atomic.LoadUint64(&a.allocationCount),
+		}
+	}
+	return ArenaStats{
+		HighWaterMark:   a.highWaterMark,
+		AllocationCount: a.allocationCount,
+	}
+}
+
+// DataStart returns the offset where user data begins. The first DataStart() bytes of
+// Memory are reserved as cache-line alignment padding, so the usable size is
+// Capacity - DataStart().
+func (a *Arena) DataStart() uintptr {
+	return a.dataStart
+}
+
+// UsedBytes returns the region holding allocations, from DataStart to NextAllocation, as one
+// slice, e.g. to copy everything to a GPU buffer or a file in a single call. The slice aliases
+// arena memory: it reflects later writes and must not be used after the memory is reset.
+func (a *Arena) UsedBytes() []byte {
+	return a.bytes()[a.dataStart:a.usedEnd()]
+}
+
+// pointerAt converts an address returned by Allocate into a pointer derived from basePtr,
+// keeping the connection to the original allocation for the race detector's checkptr validation.
+func (a *Arena) pointerAt(address uintptr) unsafe.Pointer {
+	return unsafe.Add(unsafe.Pointer(a.basePtr), address-a.Memory)
+}
+
+// MustAllocate is like Allocate but panics if the allocation fails, for arenas pre-sized for
+// their workload where running out of memory is a programmer error.
+func (a *Arena) MustAllocate(size uintptr) uintptr {
+	address, err := a.Allocate(size)
+	if err != nil {
+		panic(fmt.Sprintf("Arena.MustAllocate of %d bytes failed: %v", size, err))
+	}
+	return address
+}
+
+// AllocateBytes allocates size bytes like Allocate and returns them as a byte slice
+// backed by arena memory, with len == cap == size. A zero size returns an empty, non-nil
+// slice and a nil error without advancing NextAllocation.
+func (a *Arena) AllocateBytes(size uintptr) ([]byte, error) {
+	address, err := a.Allocate(size)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	return unsafe.Slice((*byte)(a.pointerAt(address)), size), nil
+}
+
+// AllocateSIMD16 allocates size bytes whose start address is 16-byte aligned, for assembly
+// kernels that assume aligned 128-bit loads. Only the start is aligned; the length is not
+// rounded up, and up to 15 bytes of padding may be skipped before the block.
+func (a *Arena) AllocateSIMD16(size uintptr) ([]byte, error) {
+	return a.allocateAlignedBytes(size, 16)
+}
+
+// AllocateSIMD32 is like AllocateSIMD16 for 256-bit loads, skipping up to 31 bytes of padding.
+func (a *Arena) AllocateSIMD32(size uintptr) ([]byte, error) {
+	return a.allocateAlignedBytes(size, 32)
+}
+
+func (a *Arena) allocateAlignedBytes(size uintptr, alignment uintptr) ([]byte, error) {
+	address, err := a.AllocateAligned(size, alignment)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		return []byte{}, nil
+	}
+	return unsafe.Slice((*byte)(a.pointerAt(address)), size), nil
+}
+
+// CreateChild reserves size bytes from the arena and returns an independent Arena over them,
+// e.g. to hand a worker goroutine its own allocator without contention. The child inherits
+// the parent's CacheLineSize and default alignment. It becomes invalid once the parent resets
+// past its region.
+func (a *Arena) CreateChild(size uintptr) (*Arena, error) {
+	if size == 0 {
+		return nil, ErrEmptyMemory
+	}
+	memory, err := a.AllocateBytes(size)
+	if err != nil {
+		return nil, err
+	}
+	return NewArena(memory, ArenaWithCacheLineSize(a.CacheLineSize), ArenaWithDefaultAlignment(a.defaultAlignment))
+}
+
+// bytes returns the whole memory block as a byte slice derived from basePtr. It never extends
+// past the real backing memory, even if the exported Capacity was raised beyond it.
+func (a *Arena) bytes() []byte {
+	return unsafe.Slice(a.basePtr, min(a.Capacity, a.length))
+}
+
 // InitializePersistentMemory marks the end of the persistent region.
 // All future allocations up to this point are considered persistent (retained across frames).
 func (a *Arena) InitializePersistentMemory() {
@@ -118,12 +535,86 @@ func (a *Arena) InitializePersistentMemory() {
 // to the boundary, achieving O(1) performance for frame-to-frame reset.
 func (a *Arena) ResetEphemeralMemory() {
 	a.NextAllocation = a.ArenaResetOffset
+	a.topOffset = a.Capacity
+	a.dropCheckpointsAbove(a.ArenaResetOffset)
+	a.dropRecordsFrom(a.ArenaResetOffset)
+	a.advanceGeneration(a.ArenaResetOffset)
 
 	// In a production system, you might optionally zero out the memory from
 	// the reset offset to the current end to clear stale data, though this
 	// would trade speed for safety/cleanness.
 }
 
+// Reset rewinds the arena to its initial alignment offset, discarding the persistent region,
+// all checkpoints and AllocateFromTop blocks, so it can be reused for a new task without
+// reallocating Memory.
+func (a *Arena) Reset() {
+	a.NextAllocation = a.dataStart
+	a.ArenaResetOffset = a.dataStart
+	a.topOffset = a.Capacity
+	a.checkpoints = a.checkpoints[:0]
+	a.dropRecordsFrom(a.dataStart)
+	a.advanceGeneration(a.dataStart)
+}
+
+// advanceGeneration starts a new generation after a reset that reclaimed memory from offset.
+func (a *Arena) advanceGeneration(offset uintptr) {
+	a.generation++
+	a.resets.record(a.generation, offset)
+}
+
+// dropRecordsFrom removes the diagnostic records of allocations reclaimed by a reset to offset.
+func (a *Arena) dropRecordsFrom(offset uintptr) {
+	a.allocationLog.dropFrom(offset)
+	a.allocationTags.dropFrom(offset)
+	a.typedLayout.dropFrom(offset)
+}
+
+// Generation returns the number of resets (ResetEphemeralMemory, Reset or ResetToCheckpoint)
+// so far. Code that expects fresh ephemeral data can record it and later check Valid, e.g. to
+// catch a missed or doubled reset between frames.
+func (a *Arena) Generation() uint64 {
+	return a.generation
+}
+
+// Valid reports whether generation is still the current one, i.e. no reset has happened since
+// it was obtained from Generation.
+func (a *Arena) Valid(generation uint64) bool {
+	return generation == a.generation
+}
+
+// PushPersistentCheckpoint records the current NextAllocation as a checkpoint for layered
+// initialization (e.g. engine defaults, then user config, then runtime) and returns its index.
+func (a *Arena) PushPersistentCheckpoint() int {
+	a.checkpoints = append(a.checkpoints, a.NextAllocation)
+	return len(a.checkpoints) - 1
+}
+
+// ResetToCheckpoint rewinds NextAllocation to the checkpoint at index, reclaiming everything
+// allocated after it, including all AllocateFromTop blocks. Checkpoints deeper than index are
+// invalidated. If the checkpoint was pushed before InitializePersistentMemory, the persistent
+// region shrinks back to it as well, so later allocations can never overlap what
+// ArenaResetOffset still claims is persistent.
+func (a *Arena) ResetToCheckpoint(index int) error {
+	if index < 0 || index >= len(a.checkpoints) {
+		return fmt.Errorf("checkpoint index out of bounds: %d, checkpoints: %d", index, len(a.checkpoints))
+	}
+	a.NextAllocation = a.checkpoints[index]
+	a.ArenaResetOffset = min(a.ArenaResetOffset, a.NextAllocation)
+	a.topOffset = a.Capacity
+	a.checkpoints = a.checkpoints[:index+1]
+	a.dropRecordsFrom(a.NextAllocation)
+	a.advanceGeneration(a.NextAllocation)
+	return nil
+}
+
+// dropCheckpointsAbove invalidates checkpoints that lie beyond offset.
+func (a *Arena) dropCheckpointsAbove(offset uintptr) {
+	for len(a.checkpoints) > 0 && a.checkpoints[len(a.checkpoints)-1] > offset {
+		a.checkpoints = a.checkpoints[:len(a.checkpoints)-1]
+	}
+}
+
 // AllocateStruct allocates space for a single instance of type T from the arena
 // and returns a pointer (*T) to that memory location.
 // This method relies on Go's 'unsafe' package to type-cast the memory address.
@@ -133,23 +624,111 @@ func AllocateStruct[T any](a *Arena) (*T, error) {
 	return AllocateStructObject(a, zero)
 
 }
+
+// MustAllocateStruct is like AllocateStruct but panics if the allocation fails.
+func MustAllocateStruct[T any](a *Arena) *T {
+	ptr, err := AllocateStruct[T](a)
+	if err != nil {
+		var zero T
+		panic(fmt.Sprintf("MustAllocateStruct[%T] of %d bytes failed: %v", zero, unsafe.Sizeof(zero), err))
+	}
+	return ptr
+}
+
+// AllocateStructInit allocates a zeroed T like AllocateStruct and passes it to init before
+// returning it, so construction logic lives in one place. init is not called if the
+// allocation fails, and may be nil.
+func AllocateStructInit[T any](a *Arena, init func(*T)) (*T, error) {
+	ptr, err := AllocateStruct[T](a)
+	if err != nil {
+		return nil, err
+	}
+	if init != nil {
+		init(ptr)
+	}
+	return ptr, nil
+}
+
+// CopyStruct allocates space for T and copies value into it, e.g.
+// p, _ := CopyStruct(arena, MyStruct{X: 1, Y: 2}). It is an alias for AllocateStructObject.
+func CopyStruct[T any](a *Arena, value T) (*T, error) {
+	return AllocateStructObject(a, value)
+}
+
+// AllocateStructObject allocates space for obj's type from the arena and copies obj into it.
+// Zero-size types (e.g. struct{}) always succeed with a valid non-nil pointer and consume
+// no arena memory.
 func AllocateStructObject[T any](a *Arena, obj T) (*T, error) {
 	// 1. Determine the size and alignment requirements for the type T
 	size := unsafe.Sizeof(obj)
+	alignment := unsafe.Alignof(obj)
+
+	// a. Zero-size values need no storage; new(T) does not allocate for them either
+	if size == 0 {
+		return new(T), nil
+	}
 
-	// b. Add the offset to get the address of the newly allocated struct's memory
-	structAddress, err := a.Allocate(size)
+	// b. Pad only up to T's alignment, so consecutive structs are packed without gaps
+	structAddress, err := a.AllocateAligned(size, alignment)
 	if err != nil {
 		return nil, err
 	}
 
 	// c. Convert uintptr address to pointer using unsafe.Add to maintain connection
 	// to the original allocation for race detector validation
-	basePtr := uintptr(unsafe.Pointer(a.basePtr))
-	offset := structAddress - basePtr
-	ptr := (*T)(unsafe.Add(unsafe.Pointer(a.basePtr), offset))
+	ptr := (*T)(a.pointerAt(structAddress))
 
 	// Copy the obj data into the allocated struct
 	*ptr = obj
+	recordType[T](a, structAddress, 1)
+	return ptr, nil
+}
+
+// AllocateStructAligned allocates a zeroed T whose address is aligned to alignment, which must
+// be a power of two no smaller than T's natural alignment, e.g. 64 to keep values written by
+// different goroutines on separate cache lines and avoid false sharing.
+func AllocateStructAligned[T any](a *Arena, alignment uintptr) (*T, error) {
+	var zero T
+	if alignment < unsafe.Alignof(zero) {
+		return nil, fmt.Errorf("alignment %d is less than the natural alignment %d of %T", alignment, unsafe.Alignof(zero), zero)
+	}
+	if unsafe.Sizeof(zero) == 0 {
+		return new(T), nil
+	}
+
+	address, err := a.AllocateAligned(unsafe.Sizeof(zero), alignment)
+	if err != nil {
+		return nil, err
+	}
+	ptr := (*T)(a.pointerAt(address))
+	*ptr = zero
+	recordType[T](a, address, 1)
 	return ptr, nil
 }
+
+// AllocateStructArray allocates a contiguous, zeroed array of n values of type T with a
+// single allocation aligned to T's alignment, and returns it as a []T backed by arena memory.
+// Empty arrays and arrays of zero-size elements consume no arena memory.
+func AllocateStructArray[T any](a *Arena, n int32) ([]T, error) {
+	if n < 0 {
+		return nil, ErrInvalidSize
+	}
+	var zero T
+	elementSize := unsafe.Sizeof(zero)
+	if n == 0 || elementSize == 0 {
+		return make([]T, n), nil
+	}
+	if uintptr(n) > ^uintptr(0)/elementSize {
+		return nil, ErrCapacityExceeded
+	}
+
+	address, err := a.AllocateAligned(uintptr(n)*elementSize, unsafe.Alignof(zero))
+	if err != nil {
+		return nil, err
+	}
+
+	items := unsafe.Slice((*T)(a.pointerAt(address)), n)
+	clear(items)
+	recordType[T](a, address, n)
+	return items, nil
+}
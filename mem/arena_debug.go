@@ -0,0 +1,34 @@
+package mem
+
+import "unsafe"
+
+// ArenaRef is a pointer into arena memory that carries the arena generation it was
+// allocated in. Built with the arenadebug tag, Get panics if the memory was reclaimed
+// by a reset since; in release builds the check compiles out and Get just returns the pointer.
+type ArenaRef[T any] struct {
+	arena      *Arena
+	ptr        *T
+	generation uint64
+}
+
+// AllocateStructRef allocates a zeroed T like AllocateStruct and wraps it in an ArenaRef.
+func AllocateStructRef[T any](a *Arena) (ArenaRef[T], error) {
+	ptr, err := AllocateStruct[T](a)
+	if err != nil {
+		return ArenaRef[T]{}, err
+	}
+	return ArenaRef[T]{arena: a, ptr: ptr, generation: a.generation}, nil
+}
+
+// Valid reports whether the arena has not been reset since the ref was allocated. Unlike the
+// arenadebug check in Get, it is conservative: any reset invalidates the ref, even one that
+// did not reclaim its memory.
+func (r ArenaRef[T]) Valid() bool {
+	return r.arena.Valid(r.generation)
+}
+
+// Get returns the underlying pointer, checking for use-after-reset in arenadebug builds.
+func (r ArenaRef[T]) Get() *T {
+	r.arena.checkGeneration(r.generation, unsafe.Pointer(r.ptr))
+	return r.ptr
+}
@@ -0,0 +1,11 @@
+//go:build !arenadebug
+
+package mem
+
+import "unsafe"
+
+type resetHistory struct{}
+
+func (h *resetHistory) record(generation uint64, offset uintptr) {}
+
+func (a *Arena) checkGeneration(generation uint64, ptr unsafe.Pointer) {}
@@ -0,0 +1,55 @@
+//go:build arenadebug
+
+package mem
+
+import (
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// resetHistory records, per reset, the generation it started and the offset it rewound to.
+// A reset that rewound at least as far as a later one can never decide a check, so only
+// entries with increasing offsets are kept; with the usual reset to the same offset every
+// frame the history holds a single entry.
+type resetHistory struct {
+	entries []resetEntry
+}
+
+type resetEntry struct {
+	generation uint64
+	offset     uintptr
+}
+
+func (h *resetHistory) record(generation uint64, offset uintptr) {
+	n := len(h.entries)
+	for n > 0 && h.entries[n-1].offset >= offset {
+		n--
+	}
+	h.entries = append(h.entries[:n], resetEntry{generation: generation, offset: offset})
+}
+
+// reclaimedSince returns the lowest offset any reset after generation rewound to, or ok false
+// if there was no such reset.
+func (h *resetHistory) reclaimedSince(generation uint64) (uintptr, bool) {
+	i := sort.Search(len(h.entries), func(i int) bool { return h.entries[i].generation > generation })
+	if i == len(h.entries) {
+		return 0, false
+	}
+	return h.entries[i].offset, true
+}
+
+// checkGeneration panics if ptr was allocated in an older generation and lies in memory
+// that a reset has since reclaimed. Memory below the offset every later reset rewound to
+// survived them, and pointers outside the arena (zero-size values) are never reclaimed.
+func (a *Arena) checkGeneration(generation uint64, ptr unsafe.Pointer) {
+	if generation == a.generation {
+		return
+	}
+	offset := uintptr(ptr) - a.Memory
+	reclaimed, ok := a.resets.reclaimedSince(generation)
+	if !ok || offset < reclaimed || offset >= a.Capacity {
+		return
+	}
+	panic(fmt.Sprintf("arena use-after-reset: pointer at offset %d from generation %d used in generation %d", offset, generation, a.generation))
+}
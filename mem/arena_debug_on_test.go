@@ -0,0 +1,115 @@
+//go:build arenadebug
+
+package mem
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArenaDebug_UseAfterReset(t *testing.T) {
+	type node struct {
+		Value int64
+	}
+
+	expectPanic := func(t *testing.T, f func()) {
+		t.Helper()
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("expected use-after-reset panic")
+			}
+			if !strings.Contains(r.(string), "use-after-reset") {
+				t.Errorf("expected use-after-reset message, got %v", r)
+			}
+		}()
+		f()
+	}
+
+	t.Run("detects ephemeral use after ResetEphemeralMemory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.InitializePersistentMemory()
+		ref, _ := AllocateStructRef[node](arena)
+
+		arena.ResetEphemeralMemory()
+
+		expectPanic(t, func() { ref.Get() })
+	})
+
+	t.Run("detects use after full Reset", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ref, _ := AllocateStructRef[node](arena)
+		arena.InitializePersistentMemory()
+
+		arena.Reset()
+
+		expectPanic(t, func() { ref.Get() })
+	})
+
+	t.Run("detects use after ResetToCheckpoint", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		checkpoint := arena.PushPersistentCheckpoint()
+		ref, _ := AllocateStructRef[node](arena)
+
+		arena.ResetToCheckpoint(checkpoint)
+
+		expectPanic(t, func() { ref.Get() })
+	})
+
+	t.Run("allows persistent references after ephemeral reset", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ref, _ := AllocateStructRef[node](arena)
+		arena.InitializePersistentMemory()
+
+		arena.ResetEphemeralMemory()
+
+		ref.Get().Value = 1
+	})
+
+	t.Run("allows references below the checkpoint a reset rewound to", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.PushPersistentCheckpoint()
+		ref, _ := AllocateStructRef[node](arena)
+		arena.PushPersistentCheckpoint()
+		reclaimed, _ := AllocateStructRef[node](arena)
+
+		arena.ResetToCheckpoint(1)
+
+		ref.Get().Value = 1
+		expectPanic(t, func() { reclaimed.Get() })
+	})
+
+	t.Run("detects use after a later reset rewinds further", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.PushPersistentCheckpoint()
+		ref, _ := AllocateStructRef[node](arena)
+		arena.PushPersistentCheckpoint()
+		AllocateStructRef[node](arena)
+
+		arena.ResetToCheckpoint(1)
+		ref.Get().Value = 1
+		arena.ResetToCheckpoint(0)
+
+		expectPanic(t, func() { ref.Get() })
+	})
+
+	t.Run("repeated resets to the same offset keep a single history entry", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		for range 100 {
+			AllocateStructRef[node](arena)
+			arena.ResetEphemeralMemory()
+		}
+
+		if len(arena.resets.entries) != 1 {
+			t.Errorf("expected 1 history entry, got %d", len(arena.resets.entries))
+		}
+	})
+
+	t.Run("references from the current generation are valid", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.ResetEphemeralMemory()
+		ref, _ := AllocateStructRef[node](arena)
+
+		ref.Get().Value = 1
+	})
+}
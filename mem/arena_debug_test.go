@@ -0,0 +1,63 @@
+package mem
+
+import (
+	"testing"
+)
+
+func TestAllocateStructRef(t *testing.T) {
+	type node struct {
+		Value int64
+	}
+
+	t.Run("returns a usable zeroed reference", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		ref, err := AllocateStructRef[node](arena)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if ref.Get().Value != 0 {
+			t.Errorf("expected zeroed value, got %d", ref.Get().Value)
+		}
+		ref.Get().Value = 42
+		if ref.Get().Value != 42 {
+			t.Errorf("expected Value = 42, got %d", ref.Get().Value)
+		}
+	})
+
+	t.Run("Valid turns false after any reset", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ref, _ := AllocateStructRef[node](arena)
+		arena.InitializePersistentMemory()
+
+		if !ref.Valid() {
+			t.Error("expected a fresh reference to be valid")
+		}
+		arena.ResetEphemeralMemory()
+		if ref.Valid() {
+			t.Error("expected the reference to be invalid after a reset")
+		}
+	})
+
+	t.Run("persistent references survive ephemeral resets", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ref, _ := AllocateStructRef[node](arena)
+		arena.InitializePersistentMemory()
+
+		arena.ResetEphemeralMemory()
+
+		ref.Get().Value = 7
+		if ref.Get().Value != 7 {
+			t.Errorf("expected Value = 7, got %d", ref.Get().Value)
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+
+		if _, err := AllocateStructRef[node](arena); err == nil {
+			t.Error("expected error when capacity exceeded")
+		}
+	})
+}
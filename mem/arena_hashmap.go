@@ -0,0 +1,97 @@
+package mem
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrHashMapFull is returned by HashMap.Put when every entry slot is in use.
+var ErrHashMapFull = errors.New("hash map is full")
+
+// HashMap is a fixed-capacity map keyed by HashElementId whose buckets and entries are carved
+// from an arena, e.g. for frame-scoped caches. Keys are bucketed by Id; keys whose Ids collide
+// are told apart by StringId, so two keys are the same only if both Id and StringId match.
+// The StringIds are kept on the Go heap so the garbage collector still sees them; values live
+// in arena memory and, like any arena allocation, must not hold the only reference to heap
+// objects. A HashMap is not safe for concurrent use.
+type HashMap[V any] struct {
+	buckets   []int32
+	entries   []hashMapEntry[V]
+	stringIds []string
+}
+
+type hashMapEntry[V any] struct {
+	id    uint32
+	next  int32
+	value V
+}
+
+// NewHashMap allocates a map for up to capacity entries from arena, with one bucket per entry.
+// capacity must be positive.
+func NewHashMap[V any](arena *Arena, capacity int32) (*HashMap[V], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("hash map capacity must be positive, got %d", capacity)
+	}
+	buckets, err := AllocateStructArray[int32](arena, capacity)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := AllocateStructArray[hashMapEntry[V]](arena, capacity)
+	if err != nil {
+		return nil, err
+	}
+	for i := range buckets {
+		buckets[i] = -1
+	}
+	return &HashMap[V]{
+		buckets:   buckets,
+		entries:   entries[:0],
+		stringIds: make([]string, 0, capacity),
+	}, nil
+}
+
+// Put stores value under key, overwriting the value of an equal key. It returns
+// ErrHashMapFull if key is new and the map already holds capacity entries.
+func (m *HashMap[V]) Put(key HashElementId, value V) error {
+	if index, ok := m.find(key); ok {
+		m.entries[index].value = value
+		return nil
+	}
+	if len(m.entries) == cap(m.entries) {
+		return ErrHashMapFull
+	}
+
+	bucket := m.bucket(key.Id)
+	m.entries = append(m.entries, hashMapEntry[V]{id: key.Id, next: m.buckets[bucket], value: value})
+	m.stringIds = append(m.stringIds, key.StringId)
+	m.buckets[bucket] = int32(len(m.entries) - 1)
+	return nil
+}
+
+// Get returns the value stored under key; ok is false if key is not in the map.
+func (m *HashMap[V]) Get(key HashElementId) (V, bool) {
+	if index, ok := m.find(key); ok {
+		return m.entries[index].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Len returns the number of entries in the map.
+func (m *HashMap[V]) Len() int32 {
+	return int32(len(m.entries))
+}
+
+func (m *HashMap[V]) find(key HashElementId) (int32, bool) {
+	for index := m.buckets[m.bucket(key.Id)]; index != -1; index = m.entries[index].next {
+		if m.entries[index].id == key.Id && m.stringIds[index] == key.StringId {
+			return index, true
+		}
+	}
+	return -1, false
+}
+
+func (m *HashMap[V]) bucket(id uint32) int32 {
+	// Perform modulo with uint32 first to avoid negative results, then cast to int32
+	return int32(id % uint32(len(m.buckets)))
+}
@@ -0,0 +1,120 @@
+package mem
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHashMap(t *testing.T) {
+	newMap := func(t *testing.T, capacity int32) *HashMap[int] {
+		t.Helper()
+		m, err := NewHashMap[int](NewArenaWithSizeUnsafe(4096), capacity)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return m
+	}
+
+	t.Run("inserts and retrieves values", func(t *testing.T) {
+		m := newMap(t, 16)
+		a := HashString("a", 0)
+		b := HashString("b", 0)
+
+		m.Put(a, 1)
+		m.Put(b, 2)
+
+		if value, ok := m.Get(a); !ok || value != 1 {
+			t.Errorf("expected 1, got %d (ok=%v)", value, ok)
+		}
+		if value, ok := m.Get(b); !ok || value != 2 {
+			t.Errorf("expected 2, got %d (ok=%v)", value, ok)
+		}
+		if m.Len() != 2 {
+			t.Errorf("expected Len 2, got %d", m.Len())
+		}
+	})
+
+	t.Run("missing key is not found", func(t *testing.T) {
+		m := newMap(t, 16)
+		m.Put(HashString("a", 0), 1)
+
+		if value, ok := m.Get(HashString("missing", 0)); ok || value != 0 {
+			t.Errorf("expected zero value and ok=false, got %d (ok=%v)", value, ok)
+		}
+	})
+
+	t.Run("overwrites an existing key", func(t *testing.T) {
+		m := newMap(t, 16)
+		key := HashString("a", 0)
+
+		m.Put(key, 1)
+		m.Put(key, 2)
+
+		if value, _ := m.Get(key); value != 2 {
+			t.Errorf("expected overwritten value 2, got %d", value)
+		}
+		if m.Len() != 1 {
+			t.Errorf("expected Len 1, got %d", m.Len())
+		}
+	})
+
+	t.Run("resolves Id collisions by StringId", func(t *testing.T) {
+		m := newMap(t, 16)
+		first := HashElementId{Id: 42, StringId: "first"}
+		second := HashElementId{Id: 42, StringId: "second"}
+
+		m.Put(first, 1)
+		m.Put(second, 2)
+
+		if value, _ := m.Get(first); value != 1 {
+			t.Errorf("expected 1 for first, got %d", value)
+		}
+		if value, _ := m.Get(second); value != 2 {
+			t.Errorf("expected 2 for second, got %d", value)
+		}
+		if _, ok := m.Get(HashElementId{Id: 42, StringId: "third"}); ok {
+			t.Error("expected an unknown StringId with a colliding Id not to be found")
+		}
+		if m.Len() != 2 {
+			t.Errorf("expected Len 2, got %d", m.Len())
+		}
+	})
+
+	t.Run("chains keys sharing a bucket", func(t *testing.T) {
+		m := newMap(t, 4)
+		for id := uint32(0); id < 4; id++ {
+			m.Put(HashElementId{Id: id * 4}, int(id))
+		}
+
+		for id := uint32(0); id < 4; id++ {
+			if value, ok := m.Get(HashElementId{Id: id * 4}); !ok || value != int(id) {
+				t.Errorf("expected %d for Id %d, got %d (ok=%v)", id, id*4, value, ok)
+			}
+		}
+	})
+
+	t.Run("returns ErrHashMapFull when capacity is reached", func(t *testing.T) {
+		m := newMap(t, 2)
+		m.Put(HashNumber(1, 0), 1)
+		m.Put(HashNumber(2, 0), 2)
+
+		if err := m.Put(HashNumber(3, 0), 3); !errors.Is(err, ErrHashMapFull) {
+			t.Errorf("expected ErrHashMapFull, got %v", err)
+		}
+		if err := m.Put(HashNumber(1, 0), 10); err != nil {
+			t.Errorf("expected overwriting in a full map to succeed, got %v", err)
+		}
+	})
+
+	t.Run("rejects non-positive capacity", func(t *testing.T) {
+		if _, err := NewHashMap[int](NewArenaWithSizeUnsafe(64), 0); err == nil {
+			t.Error("expected error for zero capacity")
+		}
+	})
+
+	t.Run("returns error when the arena is too small", func(t *testing.T) {
+		if _, err := NewHashMap[int](NewArenaWithSizeUnsafe(64), 1024); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package mem
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"unsafe"
+)
+
+// ErrChecksumMismatch is returned by LoadArena when the header or the memory read back does
+// not match its checksum, i.e. the stream was corrupted.
+var ErrChecksumMismatch = errors.New("arena checksum mismatch")
+
+// arenaHeader is written before the used bytes by WriteTo and read back by LoadArena.
+type arenaHeader struct {
+	Capacity         uint64
+	NextAllocation   uint64
+	ArenaResetOffset uint64
+	CacheLineSize    uint64
+	DataStart        uint64
+	Checksum         uint32
+	// HeaderChecksum is the CRC-32 (IEEE) of the fields above, so a corrupted header is
+	// detected before its sizes are used to allocate memory.
+	HeaderChecksum uint32
+}
+
+// checksum returns the CRC-32 (IEEE) of the header fields preceding HeaderChecksum.
+func (h arenaHeader) checksum() uint32 {
+	encoded, _ := binary.Append(nil, binary.LittleEndian, h)
+	return crc32.ChecksumIEEE(encoded[:len(encoded)-4])
+}
+
+// maxLoadCacheLineSize bounds the cache line size LoadArena accepts; no hardware cache line
+// is larger than a page.
+const maxLoadCacheLineSize = 4096
+
+// validate rejects header values that would make LoadArena allocate a nonsensical block or
+// read outside it. It runs before any memory is allocated.
+func (h arenaHeader) validate() error {
+	if h.Capacity == 0 {
+		return ErrEmptyMemory
+	}
+	if h.CacheLineSize == 0 || h.CacheLineSize&(h.CacheLineSize-1) != 0 {
+		return fmt.Errorf("invalid arena header: cache line size %d is not a power of two", h.CacheLineSize)
+	}
+	if h.CacheLineSize > maxLoadCacheLineSize {
+		return fmt.Errorf("invalid arena header: cache line size %d exceeds %d", h.CacheLineSize, maxLoadCacheLineSize)
+	}
+	if h.Capacity > math.MaxInt-2*h.CacheLineSize {
+		return fmt.Errorf("invalid arena header: capacity %d is too large", h.Capacity)
+	}
+	if h.NextAllocation > h.Capacity {
+		return fmt.Errorf("invalid arena header: NextAllocation %d > Capacity %d", h.NextAllocation, h.Capacity)
+	}
+	if h.ArenaResetOffset > h.NextAllocation {
+		return fmt.Errorf("invalid arena header: ArenaResetOffset %d > NextAllocation %d", h.ArenaResetOffset, h.NextAllocation)
+	}
+	if h.DataStart > h.ArenaResetOffset || h.DataStart >= h.CacheLineSize {
+		return fmt.Errorf("invalid arena header: data start %d must be below the cache line size %d and at most ArenaResetOffset %d", h.DataStart, h.CacheLineSize, h.ArenaResetOffset)
+	}
+	return nil
+}
+
+// WriteTo writes a header (capacity, NextAllocation, ArenaResetOffset, CacheLineSize, data
+// start and the memory and header checksums) followed by the used portion of the memory
+// block, so a precomputed region can be persisted and restored with LoadArena. Offsets past
+// Capacity, left by padding after the last allocation, are written as Capacity.
+func (a *Arena) WriteTo(w io.Writer) (int64, error) {
+	if err := a.validate(); err != nil {
+		return 0, err
+	}
+	header := arenaHeader{
+		Capacity:         uint64(a.Capacity),
+		NextAllocation:   uint64(a.usedEnd()),
+		ArenaResetOffset: uint64(min(a.ArenaResetOffset, a.Capacity)),
+		CacheLineSize:    uint64(a.CacheLineSize),
+		DataStart:        uint64(a.dataStart),
+		Checksum:         a.Checksum(),
+	}
+	header.HeaderChecksum = header.checksum()
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return 0, err
+	}
+	written := int64(binary.Size(header))
+
+	n, err := w.Write(a.bytes()[:a.usedEnd()])
+	written += int64(n)
+	return written, err
+}
+
+// LoadArena reconstructs an arena written by WriteTo into a freshly allocated memory block.
+// Offsets are preserved, so data can be found at the same offsets from Memory as before.
+// It returns an error wrapping ErrChecksumMismatch if the header or the memory was corrupted.
+func LoadArena(r io.Reader) (*Arena, error) {
+	var header arenaHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read arena header: %w", err)
+	}
+	if checksum := header.checksum(); checksum != header.HeaderChecksum {
+		return nil, fmt.Errorf("%w: header has %#08x, header fields have %#08x", ErrChecksumMismatch, header.HeaderChecksum, checksum)
+	}
+	if err := header.validate(); err != nil {
+		return nil, err
+	}
+
+	// Place the block so that DataStart is again the padding to the first cache line
+	// boundary; NewArena then derives the same dataStart and every offset keeps its alignment.
+	cacheLineSize := uintptr(header.CacheLineSize)
+	shift := (cacheLineSize - uintptr(header.DataStart)) & (cacheLineSize - 1)
+	backing := alignedMemory(int(header.Capacity)+int(cacheLineSize), cacheLineSize)
+	memory := backing[shift : shift+uintptr(header.Capacity) : shift+uintptr(header.Capacity)]
+	used := header.NextAllocation
+	if _, err := io.ReadFull(r, memory[:used]); err != nil {
+		return nil, fmt.Errorf("failed to read arena memory: %w", err)
+	}
+	if checksum := crc32.ChecksumIEEE(memory[:used]); checksum != header.Checksum {
+		return nil, fmt.Errorf("%w: header has %#08x, memory has %#08x", ErrChecksumMismatch, header.Checksum, checksum)
+	}
+
+	a, err := NewArena(memory, ArenaWithCacheLineSize(cacheLineSize))
+	if err != nil {
+		return nil, err
+	}
+	a.NextAllocation = uintptr(header.NextAllocation)
+	a.ArenaResetOffset = uintptr(header.ArenaResetOffset)
+	return a, nil
+}
+
+// AllocateAt allocates size bytes like Allocate but returns the offset of the block from
+// Memory instead of its address. Offsets stay valid across WriteTo and LoadArena, so they can
+// be stored in place of pointers to build position-independent structures.
+func (a *Arena) AllocateAt(size uintptr) (uintptr, error) {
+	address, err := a.Allocate(size)
+	if err != nil {
+		return 0, err
+	}
+	return address - a.Memory, nil
+}
+
+// At returns the size bytes at offset as a slice of arena memory.
+// It panics if the range lies outside the arena.
+func (a *Arena) At(offset uintptr, size uintptr) []byte {
+	memory := a.bytes()
+	if limit := uintptr(len(memory)); offset > limit || size > limit-offset {
+		panic(fmt.Sprintf("Arena.At range out of bounds: offset %d + size %d > capacity %d", offset, size, limit))
+	}
+	return memory[offset : offset+size : offset+size]
+}
+
+// AllocateStructAt allocates a zeroed T like AllocateStruct but returns its offset from Memory,
+// so the offset can be stored instead of a pointer and resolved again with StructAt, even in
+// an arena reloaded with LoadArena.
+func AllocateStructAt[T any](a *Arena) (uintptr, error) {
+	var zero T
+	address, err := a.AllocateAligned(unsafe.Sizeof(zero), unsafe.Alignof(zero))
+	if err != nil {
+		return 0, err
+	}
+	*(*T)(a.pointerAt(address)) = zero
+	recordType[T](a, address, 1)
+	return address - a.Memory, nil
+}
+
+// StructAt re-derives a pointer to the T stored at offset. It panics if the value would lie
+// outside the arena or offset is not aligned for T.
+func StructAt[T any](a *Arena, offset uintptr) *T {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if limit := uintptr(len(a.bytes())); offset > limit || size > limit-offset {
+		panic(fmt.Sprintf("StructAt range out of bounds: offset %d + size %d > capacity %d", offset, size, limit))
+	}
+	if (a.Memory+offset)%unsafe.Alignof(zero) != 0 {
+		panic(fmt.Sprintf("StructAt offset %d is not aligned to %d", offset, unsafe.Alignof(zero)))
+	}
+	return (*T)(a.pointerAt(a.Memory + offset))
+}
+
+// Checksum returns the CRC-32 (IEEE) of the used portion of the memory block, the same bytes
+// WriteTo persists, e.g. to verify an arena's contents ad hoc.
+func (a *Arena) Checksum() uint32 {
+	return crc32.ChecksumIEEE(a.bytes()[:a.usedEnd()])
+}
+
+// usedEnd is the number of bytes from the start of the block that hold allocations.
+// NextAllocation can sit past Capacity when the last allocation's padding overflows it.
+func (a *Arena) usedEnd() uintptr {
+	return min(a.NextAllocation, uintptr(len(a.bytes())))
+}
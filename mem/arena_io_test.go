@@ -0,0 +1,399 @@
+package mem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+func TestArena_WriteTo(t *testing.T) {
+	type record struct {
+		ID    int64
+		Value float64
+	}
+
+	t.Run("round-trips structs and offsets", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		first, _ := AllocateStructObject(arena, record{ID: 1, Value: 1.5})
+		second, _ := AllocateStructObject(arena, record{ID: 2, Value: 2.5})
+		arena.InitializePersistentMemory()
+		firstOffset := uintptr(unsafe.Pointer(first)) - arena.Memory
+		secondOffset := uintptr(unsafe.Pointer(second)) - arena.Memory
+
+		var buffer bytes.Buffer
+		n, err := arena.WriteTo(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if n != int64(buffer.Len()) {
+			t.Errorf("expected n = %d, got %d", buffer.Len(), n)
+		}
+
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error loading arena, got %v", err)
+		}
+		if loaded.Capacity != arena.Capacity {
+			t.Errorf("expected Capacity = %d, got %d", arena.Capacity, loaded.Capacity)
+		}
+		if loaded.NextAllocation != arena.NextAllocation {
+			t.Errorf("expected NextAllocation = %d, got %d", arena.NextAllocation, loaded.NextAllocation)
+		}
+		if loaded.ArenaResetOffset != arena.ArenaResetOffset {
+			t.Errorf("expected ArenaResetOffset = %d, got %d", arena.ArenaResetOffset, loaded.ArenaResetOffset)
+		}
+		if loaded.CacheLineSize != arena.CacheLineSize {
+			t.Errorf("expected CacheLineSize = %d, got %d", arena.CacheLineSize, loaded.CacheLineSize)
+		}
+
+		loadedFirst := (*record)(loaded.pointerAt(loaded.Memory + firstOffset))
+		loadedSecond := (*record)(loaded.pointerAt(loaded.Memory + secondOffset))
+		if *loadedFirst != *first {
+			t.Errorf("expected %+v, got %+v", *first, *loadedFirst)
+		}
+		if *loadedSecond != *second {
+			t.Errorf("expected %+v, got %+v", *second, *loadedSecond)
+		}
+	})
+
+	t.Run("writes only the used bytes", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(4096)
+		arena.Allocate(10)
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+
+		if buffer.Len() >= 4096 {
+			t.Errorf("expected output smaller than capacity, got %d bytes", buffer.Len())
+		}
+	})
+
+	t.Run("loaded arena continues allocating", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(100)
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, _ := LoadArena(&buffer)
+
+		before := loaded.NextAllocation
+		if _, err := loaded.Allocate(100); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loaded.NextAllocation <= before {
+			t.Error("expected NextAllocation to advance")
+		}
+	})
+
+	t.Run("returns checksum error on a corrupted stream", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		address, _ := arena.Allocate(100)
+		copy(arena.bytes()[address-arena.Memory:], "payload")
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		corrupted := buffer.Bytes()
+		corrupted[len(corrupted)-1] ^= 0xff
+
+		if _, err := LoadArena(bytes.NewReader(corrupted)); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("expected ErrChecksumMismatch, got %v", err)
+		}
+	})
+
+	t.Run("restores the data start and its alignment", func(t *testing.T) {
+		arena, _ := NewArena(alignedMemory(1024, 64)[8:])
+		arena.Allocate(8)
+		if arena.DataStart() != 56 {
+			t.Fatalf("expected DataStart = 56, got %d", arena.DataStart())
+		}
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if loaded.DataStart() != arena.DataStart() {
+			t.Errorf("expected DataStart = %d, got %d", arena.DataStart(), loaded.DataStart())
+		}
+		if (loaded.Memory+loaded.DataStart())%loaded.CacheLineSize != 0 {
+			t.Error("expected data to start on a cache line boundary")
+		}
+		if !bytes.Equal(loaded.UsedBytes(), arena.UsedBytes()) {
+			t.Errorf("expected UsedBytes of length %d, got %d", len(arena.UsedBytes()), len(loaded.UsedBytes()))
+		}
+		loaded.Reset()
+		if loaded.NextAllocation != arena.DataStart() {
+			t.Errorf("expected Reset to rewind to %d, got %d", arena.DataStart(), loaded.NextAllocation)
+		}
+	})
+
+	t.Run("rejects invalid header values before allocating", func(t *testing.T) {
+		valid := arenaHeader{Capacity: 1024, NextAllocation: 64, CacheLineSize: 64, Checksum: crc32.ChecksumIEEE(make([]byte, 64))}
+		tests := map[string]func(h *arenaHeader){
+			"valid":                             func(h *arenaHeader) {},
+			"negative capacity":                 func(h *arenaHeader) { h.Capacity |= 1 << 63 },
+			"capacity past MaxInt":              func(h *arenaHeader) { h.Capacity = math.MaxInt },
+			"NextAllocation past Capacity":      func(h *arenaHeader) { h.NextAllocation = h.Capacity + 1 },
+			"oversized cache line":              func(h *arenaHeader) { h.CacheLineSize = 1 << 40 },
+			"cache line not a power of two":     func(h *arenaHeader) { h.CacheLineSize = 48 },
+			"ArenaResetOffset past allocations": func(h *arenaHeader) { h.ArenaResetOffset = h.NextAllocation + 1 },
+			"DataStart past ArenaResetOffset":   func(h *arenaHeader) { h.DataStart = h.ArenaResetOffset + 8 },
+		}
+		for name, corrupt := range tests {
+			header := valid
+			corrupt(&header)
+			header.HeaderChecksum = header.checksum()
+
+			var buffer bytes.Buffer
+			binary.Write(&buffer, binary.LittleEndian, header)
+			buffer.Write(make([]byte, 64))
+
+			_, err := LoadArena(&buffer)
+			if name == "valid" {
+				if err != nil {
+					t.Errorf("valid: expected no error, got %v", err)
+				}
+			} else if err == nil || errors.Is(err, ErrChecksumMismatch) {
+				t.Errorf("%s: expected a header error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("returns ErrEmptyMemory for a zero capacity", func(t *testing.T) {
+		header := arenaHeader{CacheLineSize: 64}
+		header.HeaderChecksum = header.checksum()
+
+		var buffer bytes.Buffer
+		binary.Write(&buffer, binary.LittleEndian, header)
+
+		if _, err := LoadArena(&buffer); !errors.Is(err, ErrEmptyMemory) {
+			t.Errorf("expected ErrEmptyMemory, got %v", err)
+		}
+	})
+
+	t.Run("full arena with trailing padding round-trips", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(100)
+		arena.Allocate(100)
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loaded.NextAllocation != loaded.Capacity {
+			t.Errorf("expected NextAllocation = Capacity %d, got %d", loaded.Capacity, loaded.NextAllocation)
+		}
+	})
+
+	t.Run("returns error instead of crashing on any flipped header bit", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(100)
+		arena.InitializePersistentMemory()
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		stream := buffer.Bytes()
+
+		for bit := 0; bit < 8*binary.Size(arenaHeader{}); bit++ {
+			corrupted := bytes.Clone(stream)
+			corrupted[bit/8] ^= 1 << (bit % 8)
+
+			if _, err := LoadArena(bytes.NewReader(corrupted)); !errors.Is(err, ErrChecksumMismatch) {
+				t.Errorf("bit %d: expected ErrChecksumMismatch, got %v", bit, err)
+			}
+		}
+	})
+
+	t.Run("returns error on truncated stream", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(100)
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		truncated := bytes.NewReader(buffer.Bytes()[:buffer.Len()-10])
+
+		if _, err := LoadArena(truncated); err == nil {
+			t.Error("expected error for truncated stream")
+		}
+		if _, err := LoadArena(bytes.NewReader(nil)); err == nil {
+			t.Error("expected error for empty stream")
+		}
+	})
+}
+
+func TestArena_Checksum(t *testing.T) {
+	t.Run("covers only the used region", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		address, _ := arena.Allocate(64)
+		arena.bytes()[address-arena.Memory] = 1
+		before := arena.Checksum()
+
+		arena.bytes()[arena.NextAllocation] = 1
+		if arena.Checksum() != before {
+			t.Error("expected bytes past NextAllocation not to affect the checksum")
+		}
+
+		arena.bytes()[address-arena.Memory] = 2
+		if arena.Checksum() == before {
+			t.Error("expected a changed used byte to change the checksum")
+		}
+	})
+
+	t.Run("survives a round trip", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		address, _ := arena.Allocate(100)
+		copy(arena.bytes()[address-arena.Memory:], "payload")
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if loaded.Checksum() != arena.Checksum() {
+			t.Errorf("expected checksum %#08x, got %#08x", arena.Checksum(), loaded.Checksum())
+		}
+	})
+}
+
+func TestArena_AllocateAt(t *testing.T) {
+	t.Run("offsets address the same bytes after reloading", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		first, err := arena.AllocateAt(5)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		second, _ := arena.AllocateAt(3)
+		copy(arena.At(first, 5), "hello")
+		copy(arena.At(second, 3), "abc")
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got := string(loaded.At(first, 5)); got != "hello" {
+			t.Errorf("expected %q, got %q", "hello", got)
+		}
+		if got := string(loaded.At(second, 3)); got != "abc" {
+			t.Errorf("expected %q, got %q", "abc", got)
+		}
+	})
+
+	t.Run("offset matches the allocated address", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		offset, _ := arena.AllocateAt(16)
+		b := arena.At(offset, 16)
+
+		if uintptr(unsafe.Pointer(&b[0])) != arena.Memory+offset {
+			t.Error("expected At to return the bytes at Memory+offset")
+		}
+		if len(b) != 16 || cap(b) != 16 {
+			t.Errorf("expected len and cap 16, got %d and %d", len(b), cap(b))
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := arena.AllocateAt(65); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("At panics outside the arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for an out of range At")
+			}
+		}()
+		arena.At(60, 8)
+	})
+}
+
+func TestAllocateStructAt(t *testing.T) {
+	type node struct {
+		Value int64
+		// Next is the offset of the next node, 0 for none.
+		Next uintptr
+	}
+
+	t.Run("re-derives pointers from stored offsets after reloading", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		head, err := AllocateStructAt[node](arena)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		tail, _ := AllocateStructAt[node](arena)
+		StructAt[node](arena, head).Value = 1
+		StructAt[node](arena, head).Next = tail
+		StructAt[node](arena, tail).Value = 2
+
+		var buffer bytes.Buffer
+		arena.WriteTo(&buffer)
+		loaded, err := LoadArena(&buffer)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		first := StructAt[node](loaded, head)
+		if first.Value != 1 {
+			t.Errorf("expected head Value = 1, got %d", first.Value)
+		}
+		second := StructAt[node](loaded, first.Next)
+		if second.Value != 2 {
+			t.Errorf("expected tail Value = 2, got %d", second.Value)
+		}
+		second.Value = 3
+		if StructAt[node](arena, tail).Value != 2 {
+			t.Error("expected the reloaded arena to be independent of the original")
+		}
+	})
+
+	t.Run("allocates zeroed aligned structs", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.AllocateAt(3)
+
+		offset, _ := AllocateStructAt[node](arena)
+		p := StructAt[node](arena, offset)
+
+		if uintptr(unsafe.Pointer(p))%unsafe.Alignof(node{}) != 0 {
+			t.Error("expected aligned struct")
+		}
+		if *p != (node{}) {
+			t.Errorf("expected zero value, got %+v", *p)
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.AllocateAt(60)
+
+		if _, err := AllocateStructAt[node](arena); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("StructAt panics outside the arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for an out of range offset")
+			}
+		}()
+		StructAt[node](arena, 56)
+	})
+}
@@ -0,0 +1,55 @@
+package mem
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// TypedRecord describes one struct allocation in the typed layout.
+type TypedRecord struct {
+	// Offset is where the value starts, relative to Memory.
+	Offset uintptr
+	// Size is the size of the value in bytes.
+	Size uintptr
+	// TypeName is the Go type, e.g. "main.Node", or "[16]main.Node" for AllocateStructArray.
+	TypeName string
+}
+
+func (r TypedRecord) start() uintptr {
+	return r.Offset
+}
+
+// ArenaWithTypedLayout records the type of every value allocated with the AllocateStruct
+// family (AllocateStruct, AllocateStructObject, AllocateStructAligned, AllocateStructArray,
+// AllocateStructAt and the helpers built on them), for a human-readable map of the arena.
+// It is off by default; the type is only looked up when enabled.
+func ArenaWithTypedLayout() ArenaOption {
+	return func(o *ArenaOptions) {
+		o.TypedLayout = true
+	}
+}
+
+// TypedLayout returns a copy of the recorded struct allocations in allocation order, or nil if
+// the arena was not created with ArenaWithTypedLayout. Values reclaimed by a reset are removed.
+// Zero-size values live outside the arena and are never recorded.
+func (a *Arena) TypedLayout() []TypedRecord {
+	return a.typedLayout.snapshot()
+}
+
+// recordType adds count values of T allocated at address to the typed layout, if enabled.
+func recordType[T any](a *Arena, address uintptr, count int32) {
+	if a.typedLayout == nil {
+		return
+	}
+	var zero T
+	typeName := reflect.TypeFor[T]().String()
+	if count != 1 {
+		typeName = fmt.Sprintf("[%d]%s", count, typeName)
+	}
+	a.typedLayout.record(TypedRecord{
+		Offset:   address - a.Memory,
+		Size:     uintptr(count) * unsafe.Sizeof(zero),
+		TypeName: typeName,
+	})
+}
@@ -0,0 +1,80 @@
+package mem
+
+import (
+	"testing"
+)
+
+type layoutHeader struct {
+	Version uint32
+	Flags   uint32
+}
+
+type layoutNode struct {
+	Id     int64
+	Weight float64
+}
+
+func TestArena_TypedLayout(t *testing.T) {
+	newLayoutArena := func() *Arena {
+		arena, _ := NewArena(alignedMemory(1024, 64), ArenaWithTypedLayout())
+		return arena
+	}
+
+	t.Run("lists struct allocations with type names and offsets", func(t *testing.T) {
+		arena := newLayoutArena()
+
+		AllocateStruct[layoutHeader](arena)
+		AllocateStruct[layoutNode](arena)
+		AllocateStructArray[layoutNode](arena, 3)
+		arena.Allocate(16)
+
+		expected := []TypedRecord{
+			{Offset: 0, Size: 8, TypeName: "mem.layoutHeader"},
+			{Offset: 8, Size: 16, TypeName: "mem.layoutNode"},
+			{Offset: 24, Size: 48, TypeName: "[3]mem.layoutNode"},
+		}
+		layout := arena.TypedLayout()
+		if len(layout) != len(expected) {
+			t.Fatalf("expected %d records, got %+v", len(expected), layout)
+		}
+		for i := range expected {
+			if layout[i] != expected[i] {
+				t.Errorf("record %d: expected %+v, got %+v", i, expected[i], layout[i])
+			}
+		}
+	})
+
+	t.Run("records AllocateStructAligned and AllocateStructAt", func(t *testing.T) {
+		arena := newLayoutArena()
+
+		AllocateStructAligned[layoutHeader](arena, 64)
+		offset, _ := AllocateStructAt[layoutNode](arena)
+
+		layout := arena.TypedLayout()
+		if len(layout) != 2 || layout[0].TypeName != "mem.layoutHeader" || layout[1].Offset != offset {
+			t.Errorf("unexpected layout %+v", layout)
+		}
+	})
+
+	t.Run("resets drop reclaimed records", func(t *testing.T) {
+		arena := newLayoutArena()
+		AllocateStruct[layoutHeader](arena)
+		arena.InitializePersistentMemory()
+		AllocateStruct[layoutNode](arena)
+
+		arena.ResetEphemeralMemory()
+
+		if layout := arena.TypedLayout(); len(layout) != 1 || layout[0].TypeName != "mem.layoutHeader" {
+			t.Errorf("expected only the persistent header, got %+v", layout)
+		}
+	})
+
+	t.Run("is off by default", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		AllocateStruct[layoutNode](arena)
+
+		if layout := arena.TypedLayout(); layout != nil {
+			t.Errorf("expected nil layout, got %+v", layout)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package mem
+
+import "sync"
+
+// AllocationKind identifies which allocation path produced an AllocationRecord.
+type AllocationKind uint8
+
+const (
+	// AllocationKindBlock is a cache-line padded block from Allocate (and AllocateBytes,
+	// AllocateAt, CreateChild, ...).
+	AllocationKindBlock AllocationKind = iota
+	// AllocationKindAligned is a packed block from AllocateAligned, which backs the
+	// AllocateStruct family.
+	AllocationKindAligned
+)
+
+func (k AllocationKind) String() string {
+	switch k {
+	case AllocationKindBlock:
+		return "block"
+	case AllocationKindAligned:
+		return "aligned"
+	}
+	return "unknown"
+}
+
+// AllocationRecord describes one allocation in the allocation log.
+type AllocationRecord struct {
+	// Size is the requested size in bytes, excluding padding.
+	Size uintptr
+	// Offset is where the block starts, relative to Memory.
+	Offset uintptr
+	Kind   AllocationKind
+}
+
+// ArenaWithAllocationLog records every allocation for diagnostics, e.g. when tuning arena
+// sizes. It is off by default because the log grows with every allocation.
+func ArenaWithAllocationLog() ArenaOption {
+	return func(o *ArenaOptions) {
+		o.AllocationLog = true
+	}
+}
+
+// AllocationLog returns a copy of the recorded allocations in allocation order, or nil if
+// the arena was not created with ArenaWithAllocationLog. Entries for memory reclaimed by a
+// reset are removed.
+func (a *Arena) AllocationLog() []AllocationRecord {
+	return a.allocationLog.snapshot()
+}
+
+// offsetLog is the storage behind the opt-in diagnostics (ArenaWithAllocationLog,
+// ArenaWithAllocationTags, ArenaWithTypedLayout). Records are kept in allocation order with
+// their offset, so a reset can drop exactly the ones whose memory it reclaimed. The mutex
+// keeps recording safe for thread-safe arenas. A disabled log is a nil pointer, so Arena
+// holds no lock and stays copyable; all methods are no-ops on nil.
+type offsetLog[R offsetRecord] struct {
+	mu      sync.Mutex
+	records []R
+}
+
+// newOffsetLog returns an empty log, or nil when enabled is false.
+func newOffsetLog[R offsetRecord](enabled bool) *offsetLog[R] {
+	if !enabled {
+		return nil
+	}
+	return &offsetLog[R]{}
+}
+
+// offsetRecord is implemented by the entries of an offsetLog.
+type offsetRecord interface {
+	start() uintptr
+}
+
+func (r AllocationRecord) start() uintptr {
+	return r.Offset
+}
+
+func (l *offsetLog[R]) record(record R) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.records = append(l.records, record)
+	l.mu.Unlock()
+}
+
+// dropFrom removes the records of allocations starting at or beyond offset.
+func (l *offsetLog[R]) dropFrom(offset uintptr) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	kept := l.records[:0]
+	for _, record := range l.records {
+		if record.start() < offset {
+			kept = append(kept, record)
+		}
+	}
+	l.records = kept
+	l.mu.Unlock()
+}
+
+// snapshot returns a copy of the records, or nil if the log is disabled.
+func (l *offsetLog[R]) snapshot() []R {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]R{}, l.records...)
+}
@@ -0,0 +1,96 @@
+package mem
+
+import (
+	"testing"
+)
+
+func TestArena_AllocationLog(t *testing.T) {
+	newLoggedArena := func() *Arena {
+		arena, _ := NewArena(alignedMemory(1024, 64), ArenaWithAllocationLog())
+		return arena
+	}
+
+	t.Run("records sizes, increasing offsets and kinds", func(t *testing.T) {
+		arena := newLoggedArena()
+
+		arena.Allocate(100)
+		AllocateStruct[int64](arena)
+		arena.AllocateBytes(10)
+
+		log := arena.AllocationLog()
+		expected := []AllocationRecord{
+			{Size: 100, Offset: 0, Kind: AllocationKindBlock},
+			{Size: 8, Offset: 128, Kind: AllocationKindAligned},
+			{Size: 10, Offset: 192, Kind: AllocationKindBlock},
+		}
+		if len(log) != len(expected) {
+			t.Fatalf("expected %d records, got %+v", len(expected), log)
+		}
+		for i := range expected {
+			if log[i] != expected[i] {
+				t.Errorf("record %d: expected %+v, got %+v", i, expected[i], log[i])
+			}
+		}
+	})
+
+	t.Run("ResetEphemeralMemory clears ephemeral entries", func(t *testing.T) {
+		arena := newLoggedArena()
+		arena.Allocate(16)
+		arena.InitializePersistentMemory()
+		arena.Allocate(16)
+		arena.Allocate(16)
+
+		arena.ResetEphemeralMemory()
+
+		if log := arena.AllocationLog(); len(log) != 1 || log[0].Offset != 0 {
+			t.Errorf("expected only the persistent record, got %+v", log)
+		}
+	})
+
+	t.Run("Reset and ResetToCheckpoint clear reclaimed entries", func(t *testing.T) {
+		arena := newLoggedArena()
+		arena.Allocate(16)
+		checkpoint := arena.PushPersistentCheckpoint()
+		arena.Allocate(16)
+
+		arena.ResetToCheckpoint(checkpoint)
+		if log := arena.AllocationLog(); len(log) != 1 {
+			t.Errorf("expected 1 record after ResetToCheckpoint, got %+v", log)
+		}
+
+		arena.Reset()
+		if log := arena.AllocationLog(); len(log) != 0 {
+			t.Errorf("expected no records after Reset, got %+v", log)
+		}
+	})
+
+	t.Run("is off by default", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(16)
+
+		if log := arena.AllocationLog(); log != nil {
+			t.Errorf("expected nil log, got %+v", log)
+		}
+		if arena.allocationLog != nil || arena.allocationTags != nil || arena.typedLayout != nil {
+			t.Error("expected no log storage to be allocated when the options are off")
+		}
+	})
+
+	t.Run("returned log is a copy", func(t *testing.T) {
+		arena := newLoggedArena()
+		arena.Allocate(16)
+
+		log := arena.AllocationLog()
+		log[0].Size = 999
+
+		if arena.AllocationLog()[0].Size != 16 {
+			t.Error("expected the arena's log to be unaffected")
+		}
+	})
+
+	t.Run("kinds have readable names", func(t *testing.T) {
+		if AllocationKindBlock.String() != "block" || AllocationKindAligned.String() != "aligned" {
+			t.Errorf("unexpected names %q and %q", AllocationKindBlock, AllocationKindAligned)
+		}
+	})
+}
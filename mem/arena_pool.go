@@ -0,0 +1,94 @@
+package mem
+
+import (
+	"sync"
+)
+
+// ArenaPool recycles fixed-size arenas through a sync.Pool, e.g. one arena per request,
+// instead of reallocating the backing memory each time.
+type ArenaPool struct {
+	pool      sync.Pool
+	size      int
+	zeroOnPut bool
+}
+
+type ArenaPoolOptions struct {
+	ArenaOptions []ArenaOption
+	ZeroOnPut    bool
+}
+
+type ArenaPoolOption func(*ArenaPoolOptions)
+
+// ArenaPoolWithArenaOptions sets the options used to create each pooled arena.
+func ArenaPoolWithArenaOptions(options ...ArenaOption) ArenaPoolOption {
+	return func(o *ArenaPoolOptions) {
+		o.ArenaOptions = options
+	}
+}
+
+// ArenaPoolWithZeroOnPut clears the memory of arenas returned to the pool, including data
+// left behind by earlier resets and AllocateFromTop blocks.
+func ArenaPoolWithZeroOnPut() ArenaPoolOption {
+	return func(o *ArenaPoolOptions) {
+		o.ZeroOnPut = true
+	}
+}
+
+// NewArenaPool creates a pool handing out arenas of size bytes. The pool grows on demand.
+func NewArenaPool(size int, options ...ArenaPoolOption) (*ArenaPool, error) {
+	opts := ArenaPoolOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	arenaOpts := defaultArenaOptions()
+	for _, option := range opts.ArenaOptions {
+		option(&arenaOpts)
+	}
+
+	newArena := func() (*Arena, error) {
+		return NewArena(alignedMemory(size, arenaOpts.CacheLineSize), opts.ArenaOptions...)
+	}
+	// Validate the configuration up front so Get never has to return an error.
+	first, err := newArena()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ArenaPool{
+		size:      size,
+		zeroOnPut: opts.ZeroOnPut,
+	}
+	p.pool.New = func() any {
+		a, err := newArena()
+		if err != nil {
+			panic(err)
+		}
+		return a
+	}
+	p.pool.Put(first)
+	return p, nil
+}
+
+// Get returns a fresh arena from the pool, allocating a new one if the pool is empty.
+func (p *ArenaPool) Get() *Arena {
+	return p.pool.Get().(*Arena)
+}
+
+// Put fully resets the arena, clearing the persistent region and its Stats, and returns it to
+// the pool. Arenas of a different size are dropped. The generation keeps counting, so
+// references handed out before Put stay invalid in the next user's hands.
+func (p *ArenaPool) Put(a *Arena) {
+	if a == nil || a.Capacity != uintptr(p.size) {
+		return
+	}
+	if p.zeroOnPut {
+		// Earlier resets may have rewound past data that is still in the block, so clear all
+		// of it rather than just the currently used region.
+		clear(a.bytes())
+	}
+	a.Reset()
+	a.highWaterMark = 0
+	a.allocationCount = 0
+	p.pool.Put(a)
+}
@@ -0,0 +1,136 @@
+package mem
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArenaPool(t *testing.T) {
+	t.Run("reused arena starts fresh", func(t *testing.T) {
+		pool, err := NewArenaPool(1024)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		arena := pool.Get()
+		start := arena.NextAllocation
+		arena.Allocate(100)
+		arena.InitializePersistentMemory()
+		arena.Allocate(200)
+		pool.Put(arena)
+
+		reused := pool.Get()
+		if reused.Capacity != 1024 {
+			t.Errorf("expected Capacity = 1024, got %d", reused.Capacity)
+		}
+		if reused.NextAllocation != start {
+			t.Errorf("expected NextAllocation = %d, got %d", start, reused.NextAllocation)
+		}
+		if reused.ArenaResetOffset != start {
+			t.Errorf("expected ArenaResetOffset = %d, got %d", start, reused.ArenaResetOffset)
+		}
+	})
+
+	t.Run("grows on demand", func(t *testing.T) {
+		pool, _ := NewArenaPool(512)
+
+		first := pool.Get()
+		second := pool.Get()
+		if first == second {
+			t.Fatal("expected distinct arenas when the pool is empty")
+		}
+		if second.Capacity != 512 {
+			t.Errorf("expected Capacity = 512, got %d", second.Capacity)
+		}
+	})
+
+	t.Run("zeroes used memory on put", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024, ArenaPoolWithZeroOnPut())
+
+		arena := pool.Get()
+		value, _ := AllocateStructObject(arena, int64(0x7FFF))
+		pool.Put(arena)
+
+		if *value != 0 {
+			t.Errorf("expected memory to be zeroed, got %d", *value)
+		}
+	})
+
+	t.Run("zeroes data reclaimed by an earlier reset on Put", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024, ArenaPoolWithZeroOnPut())
+
+		arena := pool.Get()
+		address, _ := arena.Allocate(1024)
+		data := arena.At(address-arena.Memory, 1024)
+		for i := range data {
+			data[i] = 0xAA
+		}
+		arena.ResetEphemeralMemory()
+		pool.Put(arena)
+
+		if slices.ContainsFunc(data, func(b byte) bool { return b != 0 }) {
+			t.Error("expected data from before the reset to be zeroed")
+		}
+	})
+
+	t.Run("resets stats on Put", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024)
+
+		arena := pool.Get()
+		arena.Allocate(512)
+		arena.Allocate(256)
+		generation := arena.Generation()
+		pool.Put(arena)
+
+		if stats := arena.Stats(); stats != (ArenaStats{}) {
+			t.Errorf("expected zero stats, got %+v", stats)
+		}
+		if arena.Generation() <= generation {
+			t.Error("expected the generation to keep counting across Put")
+		}
+	})
+
+	t.Run("zeroes AllocateFromTop blocks on Put", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024, ArenaPoolWithZeroOnPut())
+
+		arena := pool.Get()
+		top, _ := arena.AllocateFromTop(16)
+		for i := range top {
+			top[i] = 0xCD
+		}
+		pool.Put(arena)
+
+		if slices.ContainsFunc(top, func(b byte) bool { return b != 0 }) {
+			t.Errorf("expected top block to be zeroed, got %x", top)
+		}
+	})
+
+	t.Run("applies arena options", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024, ArenaPoolWithArenaOptions(ArenaWithCacheLineSize(128)))
+
+		if pool.Get().CacheLineSize != 128 {
+			t.Error("expected pooled arenas to use CacheLineSize = 128")
+		}
+	})
+
+	t.Run("returns error for invalid configuration", func(t *testing.T) {
+		if _, err := NewArenaPool(0); err == nil {
+			t.Error("expected error for zero size")
+		}
+		if _, err := NewArenaPool(1024, ArenaPoolWithArenaOptions(ArenaWithCacheLineSize(3))); err == nil {
+			t.Error("expected error for invalid cache line size")
+		}
+	})
+
+	t.Run("drops arenas of a different size", func(t *testing.T) {
+		pool, _ := NewArenaPool(1024)
+		foreign := NewArenaWithSizeUnsafe(64)
+		foreign.Allocate(10)
+
+		pool.Put(foreign)
+
+		if foreign.NextAllocation == 0 {
+			t.Error("expected foreign arena to be left untouched")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package mem
+
+import (
+	"io"
+	"unsafe"
+)
+
+// ArenaStringBuilder builds a string inside a block reserved from an arena, like
+// strings.Builder but without heap growth. It writes through an ArenaWriter, but unlike the
+// writer's partial writes, writes that would exceed the reserved budget fail as a whole, so
+// the built string never ends in a partial write (e.g. half a UTF-8 sequence).
+type ArenaStringBuilder struct {
+	writer ArenaWriter
+}
+
+var (
+	_ io.Writer       = (*ArenaStringBuilder)(nil)
+	_ io.StringWriter = (*ArenaStringBuilder)(nil)
+	_ io.ByteWriter   = (*ArenaStringBuilder)(nil)
+)
+
+// NewArenaStringBuilder reserves maxLen bytes from arena for the string. It returns
+// ErrInvalidSize for a negative maxLen.
+func NewArenaStringBuilder(arena *Arena, maxLen int) (*ArenaStringBuilder, error) {
+	if maxLen < 0 {
+		return nil, ErrInvalidSize
+	}
+	writer, err := arena.Writer(uintptr(maxLen))
+	if err != nil {
+		return nil, err
+	}
+	return &ArenaStringBuilder{writer: *writer}, nil
+}
+
+// Write appends p, or returns ErrCapacityExceeded and writes nothing if p does not fit.
+func (b *ArenaStringBuilder) Write(p []byte) (int, error) {
+	if len(p) > b.Available() {
+		return 0, ErrCapacityExceeded
+	}
+	return b.writer.Write(p)
+}
+
+// WriteString appends s, or returns ErrCapacityExceeded and writes nothing if s does not fit.
+func (b *ArenaStringBuilder) WriteString(s string) (int, error) {
+	if len(s) > b.Available() {
+		return 0, ErrCapacityExceeded
+	}
+	return b.writer.WriteString(s)
+}
+
+// WriteByte appends c, or returns ErrCapacityExceeded if the budget is used up.
+func (b *ArenaStringBuilder) WriteByte(c byte) error {
+	if b.Available() == 0 {
+		return ErrCapacityExceeded
+	}
+	b.writer.buffer = append(b.writer.buffer, c)
+	return nil
+}
+
+// String returns the string built so far as a view over arena memory, without copying.
+// Later writes do not change it, but it is only valid until the arena memory is reset.
+func (b *ArenaStringBuilder) String() string {
+	written := b.writer.Bytes()
+	if len(written) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(written), len(written))
+}
+
+// Len returns the number of bytes written so far.
+func (b *ArenaStringBuilder) Len() int {
+	return b.writer.Len()
+}
+
+// Available returns the number of bytes that can still be written.
+func (b *ArenaStringBuilder) Available() int {
+	return b.writer.Available()
+}
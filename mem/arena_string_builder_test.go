@@ -0,0 +1,92 @@
+package mem
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+func TestArenaStringBuilder(t *testing.T) {
+	t.Run("builds a string piecewise in arena memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		builder, err := NewArenaStringBuilder(arena, 64)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		builder.WriteString("hello")
+		builder.WriteByte(',')
+		builder.Write([]byte(" "))
+		fmt.Fprintf(builder, "arena %d", 42)
+
+		result := builder.String()
+		if result != "hello, arena 42" {
+			t.Errorf("expected %q, got %q", "hello, arena 42", result)
+		}
+		if builder.Len() != len(result) || builder.Available() != 64-len(result) {
+			t.Errorf("unexpected Len %d and Available %d", builder.Len(), builder.Available())
+		}
+		address := uintptr(unsafe.Pointer(unsafe.StringData(result)))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Error("expected the string to live in arena memory")
+		}
+	})
+
+	t.Run("writes beyond the budget fail without writing", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		builder, _ := NewArenaStringBuilder(arena, 4)
+		builder.WriteString("abc")
+
+		if _, err := builder.WriteString("de"); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if _, err := builder.Write([]byte("de")); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if err := builder.WriteByte('d'); err != nil {
+			t.Errorf("expected the last byte to fit, got %v", err)
+		}
+		if err := builder.WriteByte('e'); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if builder.String() != "abcd" {
+			t.Errorf("expected %q, got %q", "abcd", builder.String())
+		}
+	})
+
+	t.Run("earlier strings are unaffected by later writes", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		builder, _ := NewArenaStringBuilder(arena, 16)
+		builder.WriteString("first")
+		first := builder.String()
+
+		builder.WriteString(" second")
+
+		if first != "first" {
+			t.Errorf("expected %q, got %q", "first", first)
+		}
+	})
+
+	t.Run("empty builder returns an empty string", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		builder, err := NewArenaStringBuilder(arena, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if builder.String() != "" {
+			t.Errorf("expected empty string, got %q", builder.String())
+		}
+	})
+
+	t.Run("returns errors for invalid or oversized budgets", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := NewArenaStringBuilder(arena, -1); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("expected ErrInvalidSize, got %v", err)
+		}
+		if _, err := NewArenaStringBuilder(arena, 65); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
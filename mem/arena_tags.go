@@ -0,0 +1,52 @@
+package mem
+
+import "unsafe"
+
+// ArenaWithAllocationTags enables per-tag accounting for AllocateTagged, e.g. to see how much
+// of an arena is used by geometry versus text. It is off by default; without it tags are
+// ignored and BytesByTag returns nil.
+func ArenaWithAllocationTags() ArenaOption {
+	return func(o *ArenaOptions) {
+		o.AllocationTags = true
+	}
+}
+
+// AllocateTagged allocates size bytes like AllocateBytes and, if the arena was created with
+// ArenaWithAllocationTags, counts them towards tag in BytesByTag.
+func (a *Arena) AllocateTagged(size uintptr, tag string) ([]byte, error) {
+	data, err := a.AllocateBytes(size)
+	if err != nil || size == 0 {
+		return data, err
+	}
+	a.allocationTags.record(taggedAllocation{offset: a.offsetOf(data), size: size, tag: tag})
+	return data, nil
+}
+
+// BytesByTag returns the total requested bytes of the live AllocateTagged allocations per tag,
+// excluding padding. Allocations reclaimed by a reset no longer count. It returns nil if the
+// arena was not created with ArenaWithAllocationTags.
+func (a *Arena) BytesByTag() map[string]uintptr {
+	if a.allocationTags == nil {
+		return nil
+	}
+	totals := make(map[string]uintptr)
+	for _, allocation := range a.allocationTags.snapshot() {
+		totals[allocation.tag] += allocation.size
+	}
+	return totals
+}
+
+// offsetOf returns the offset from Memory of a non-empty slice of arena memory.
+func (a *Arena) offsetOf(data []byte) uintptr {
+	return uintptr(unsafe.Pointer(&data[0])) - a.Memory
+}
+
+type taggedAllocation struct {
+	offset uintptr
+	size   uintptr
+	tag    string
+}
+
+func (t taggedAllocation) start() uintptr {
+	return t.offset
+}
@@ -0,0 +1,79 @@
+package mem
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestArena_AllocateTagged(t *testing.T) {
+	newTaggedArena := func() *Arena {
+		arena, _ := NewArena(alignedMemory(1024, 64), ArenaWithAllocationTags())
+		return arena
+	}
+
+	t.Run("totals bytes per tag", func(t *testing.T) {
+		arena := newTaggedArena()
+
+		arena.AllocateTagged(100, "geometry")
+		arena.AllocateTagged(30, "text")
+		arena.AllocateTagged(50, "geometry")
+		arena.Allocate(200)
+
+		expected := map[string]uintptr{"geometry": 150, "text": 30}
+		if totals := arena.BytesByTag(); !maps.Equal(totals, expected) {
+			t.Errorf("expected %v, got %v", expected, totals)
+		}
+	})
+
+	t.Run("returns arena memory", func(t *testing.T) {
+		arena := newTaggedArena()
+
+		data, err := arena.AllocateTagged(10, "text")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(data) != 10 || arena.offsetOf(data) >= arena.Capacity {
+			t.Errorf("expected 10 bytes inside the arena, got %d at offset %d", len(data), arena.offsetOf(data))
+		}
+	})
+
+	t.Run("resets drop reclaimed allocations", func(t *testing.T) {
+		arena := newTaggedArena()
+		arena.AllocateTagged(16, "persistent")
+		arena.InitializePersistentMemory()
+		arena.AllocateTagged(16, "frame")
+
+		arena.ResetEphemeralMemory()
+		if totals := arena.BytesByTag(); !maps.Equal(totals, map[string]uintptr{"persistent": 16}) {
+			t.Errorf("expected only persistent bytes, got %v", totals)
+		}
+
+		arena.Reset()
+		if totals := arena.BytesByTag(); len(totals) != 0 {
+			t.Errorf("expected no tagged bytes after Reset, got %v", totals)
+		}
+	})
+
+	t.Run("failed allocations are not counted", func(t *testing.T) {
+		arena := newTaggedArena()
+
+		if _, err := arena.AllocateTagged(4096, "huge"); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if totals := arena.BytesByTag(); len(totals) != 0 {
+			t.Errorf("expected no tagged bytes, got %v", totals)
+		}
+	})
+
+	t.Run("tags are ignored unless enabled", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		if _, err := arena.AllocateTagged(16, "text"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if totals := arena.BytesByTag(); totals != nil {
+			t.Errorf("expected nil totals, got %v", totals)
+		}
+	})
+}
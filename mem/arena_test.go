@@ -6,6 +6,11 @@
 package mem
 
 import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"unsafe"
 )
@@ -38,8 +43,8 @@ func TestNewArena(t *testing.T) {
 	t.Run("creates arena with empty memory", func(t *testing.T) {
 		memory := make([]byte, 0)
 		arena, err := NewArena(memory)
-		if err == nil {
-			t.Fatalf("expected error, got nil")
+		if !errors.Is(err, ErrEmptyMemory) {
+			t.Fatalf("expected ErrEmptyMemory, got %v", err)
 		}
 		if arena != nil {
 			t.Fatalf("expected arena to be nil, got %v", arena)
@@ -261,8 +266,8 @@ func TestArena_AllocateStruct(t *testing.T) {
 		if err2 == nil {
 			t.Fatal("expected error when capacity exceeded, got nil")
 		}
-		if err2.Error() != "arena capacity exceeded: cannot allocate required memory" {
-			t.Errorf("expected specific error message, got %v", err2)
+		if !errors.Is(err2, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err2)
 		}
 	})
 
@@ -840,3 +845,1680 @@ func TestAllocateStructObject_MyArray(t *testing.T) {
 		}
 	})
 }
+
+func TestArena_Stats(t *testing.T) {
+	t.Run("new arena has empty stats", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		stats := arena.Stats()
+		if stats.HighWaterMark != 0 || stats.AllocationCount != 0 {
+			t.Errorf("expected empty stats, got %+v", stats)
+		}
+	})
+
+	t.Run("counts raw and struct allocations", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		arena.Allocate(10)
+		AllocateStruct[int64](arena)
+
+		if arena.Stats().AllocationCount != 2 {
+			t.Errorf("expected AllocationCount = 2, got %d", arena.Stats().AllocationCount)
+		}
+	})
+
+	t.Run("failed allocations are not counted", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 100))
+
+		arena.Allocate(200)
+
+		if arena.Stats().AllocationCount != 0 {
+			t.Errorf("expected AllocationCount = 0, got %d", arena.Stats().AllocationCount)
+		}
+	})
+
+	t.Run("high-water mark reflects the largest burst across resets", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 4096))
+		arena.Allocate(64)
+		arena.InitializePersistentMemory()
+
+		bursts := []uintptr{100, 1000, 300}
+		var peak uintptr
+		for _, burst := range bursts {
+			for i := 0; i < 3; i++ {
+				arena.Allocate(burst)
+			}
+			if arena.NextAllocation > peak {
+				peak = arena.NextAllocation
+			}
+			arena.ResetEphemeralMemory()
+		}
+
+		stats := arena.Stats()
+		if stats.HighWaterMark != peak {
+			t.Errorf("expected HighWaterMark = %d, got %d", peak, stats.HighWaterMark)
+		}
+		if stats.HighWaterMark <= arena.NextAllocation {
+			t.Errorf("expected HighWaterMark (%d) above current usage (%d)", stats.HighWaterMark, arena.NextAllocation)
+		}
+		if stats.AllocationCount != 10 {
+			t.Errorf("expected AllocationCount = 10, got %d", stats.AllocationCount)
+		}
+	})
+}
+
+func TestArena_ThreadSafe(t *testing.T) {
+	const goroutines = 16
+	const allocationsPerGoroutine = 50
+	const blockSize = 40
+
+	t.Run("concurrent allocations are disjoint", func(t *testing.T) {
+		memory := make([]byte, goroutines*allocationsPerGoroutine*64)
+		arena, err := NewArena(memory, ArenaWithThreadSafe())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		addresses := make([][]uintptr, goroutines)
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < allocationsPerGoroutine; i++ {
+					address, err := arena.Allocate(blockSize)
+					if err != nil {
+						t.Errorf("goroutine %d: expected no error, got %v", g, err)
+						return
+					}
+					block := uintptrToPtr[[blockSize]byte](memory, address)
+					for j := range block {
+						block[j] = byte(g + 1)
+					}
+					addresses[g] = append(addresses[g], address)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		var all []uintptr
+		for g, owned := range addresses {
+			for _, address := range owned {
+				block := uintptrToPtr[[blockSize]byte](memory, address)
+				for j := range block {
+					if block[j] != byte(g+1) {
+						t.Fatalf("block at %d overwritten: expected sentinel %d, got %d", address, g+1, block[j])
+					}
+				}
+				all = append(all, address)
+			}
+		}
+
+		sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+		for i := 1; i < len(all); i++ {
+			if all[i]-all[i-1] < blockSize {
+				t.Fatalf("blocks overlap: %d and %d", all[i-1], all[i])
+			}
+		}
+		if arena.Stats().AllocationCount != goroutines*allocationsPerGoroutine {
+			t.Errorf("expected AllocationCount = %d, got %d", goroutines*allocationsPerGoroutine, arena.Stats().AllocationCount)
+		}
+	})
+
+	t.Run("concurrent AllocateStruct calls are disjoint", func(t *testing.T) {
+		type sentinel struct {
+			Owner int64
+			Index int64
+		}
+		arena, _ := NewArena(make([]byte, goroutines*allocationsPerGoroutine*64), ArenaWithThreadSafe())
+
+		pointers := make([][]*sentinel, goroutines)
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < allocationsPerGoroutine; i++ {
+					ptr, err := AllocateStructObject(arena, sentinel{Owner: int64(g), Index: int64(i)})
+					if err != nil {
+						t.Errorf("goroutine %d: expected no error, got %v", g, err)
+						return
+					}
+					pointers[g] = append(pointers[g], ptr)
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		for g, owned := range pointers {
+			for i, ptr := range owned {
+				if ptr.Owner != int64(g) || ptr.Index != int64(i) {
+					t.Fatalf("struct overwritten: expected {%d %d}, got %+v", g, i, *ptr)
+				}
+			}
+		}
+	})
+
+	t.Run("concurrent allocations fail cleanly when exhausted", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 64*10), ArenaWithThreadSafe())
+
+		var mu sync.Mutex
+		successes := 0
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := arena.Allocate(64); err == nil {
+					mu.Lock()
+					successes++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if successes != 10 {
+			t.Errorf("expected 10 successful allocations, got %d", successes)
+		}
+	})
+}
+
+func TestArena_CreateChild(t *testing.T) {
+	t.Run("children carve disjoint regions from the parent", func(t *testing.T) {
+		parent := NewArenaWithSizeUnsafe(4096)
+
+		children := make([]*Arena, 4)
+		for i := range children {
+			child, err := parent.CreateChild(512)
+			if err != nil {
+				t.Fatalf("expected no error creating child %d, got %v", i, err)
+			}
+			if child.Capacity != 512 {
+				t.Errorf("expected child Capacity = 512, got %d", child.Capacity)
+			}
+			if child.Memory < parent.Memory || child.Memory+child.Capacity > parent.Memory+parent.Capacity {
+				t.Errorf("child %d region outside parent memory", i)
+			}
+			children[i] = child
+		}
+
+		for i := 1; i < len(children); i++ {
+			if children[i].Memory < children[i-1].Memory+children[i-1].Capacity {
+				t.Errorf("child %d overlaps child %d", i, i-1)
+			}
+		}
+	})
+
+	t.Run("children allocate independently", func(t *testing.T) {
+		parent := NewArenaWithSizeUnsafe(4096)
+		child1, _ := parent.CreateChild(256)
+		child2, _ := parent.CreateChild(256)
+		parentNext := parent.NextAllocation
+
+		value1, err := AllocateStructObject(child1, int64(1))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		value2, _ := AllocateStructObject(child2, int64(2))
+
+		if *value1 != 1 || *value2 != 2 {
+			t.Errorf("expected values 1 and 2, got %d and %d", *value1, *value2)
+		}
+		if parent.NextAllocation != parentNext {
+			t.Error("expected child allocations not to advance the parent")
+		}
+		if child2.NextAllocation == 0 || child1.NextAllocation == 0 {
+			t.Error("expected each child to advance its own NextAllocation")
+		}
+	})
+
+	t.Run("child inherits cache line size", func(t *testing.T) {
+		parent, _ := NewArena(make([]byte, 4096), ArenaWithCacheLineSize(128))
+		child, _ := parent.CreateChild(1024)
+
+		if child.CacheLineSize != 128 {
+			t.Errorf("expected CacheLineSize = 128, got %d", child.CacheLineSize)
+		}
+	})
+
+	t.Run("returns error when parent is exhausted", func(t *testing.T) {
+		parent := NewArenaWithSizeUnsafe(256)
+
+		if _, err := parent.CreateChild(512); err == nil {
+			t.Error("expected error when child exceeds parent capacity")
+		}
+		if _, err := parent.CreateChild(0); !errors.Is(err, ErrEmptyMemory) {
+			t.Errorf("expected ErrEmptyMemory for zero-size child, got %v", err)
+		}
+	})
+}
+
+func TestArena_PersistentCheckpoints(t *testing.T) {
+	t.Run("resets to the middle checkpoint", func(t *testing.T) {
+		memory := make([]byte, 4096)
+		arena, _ := NewArena(memory)
+
+		engine, _ := arena.Allocate(64)
+		engineBuffer := uintptrToPtr[[64]byte](memory, engine)
+		engineBuffer[0] = 0xE0
+		first := arena.PushPersistentCheckpoint()
+
+		config, _ := arena.Allocate(64)
+		configBuffer := uintptrToPtr[[64]byte](memory, config)
+		configBuffer[0] = 0xC0
+		second := arena.PushPersistentCheckpoint()
+		secondOffset := arena.NextAllocation
+
+		arena.Allocate(64)
+		third := arena.PushPersistentCheckpoint()
+		arena.Allocate(128)
+
+		if first != 0 || second != 1 || third != 2 {
+			t.Fatalf("expected indices 0, 1, 2, got %d, %d, %d", first, second, third)
+		}
+
+		if err := arena.ResetToCheckpoint(second); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arena.NextAllocation != secondOffset {
+			t.Errorf("expected NextAllocation = %d, got %d", secondOffset, arena.NextAllocation)
+		}
+
+		// Memory below the checkpoint is preserved
+		if engineBuffer[0] != 0xE0 || configBuffer[0] != 0xC0 {
+			t.Error("expected memory below the checkpoint to be preserved")
+		}
+
+		// Memory above the checkpoint is reclaimable
+		reclaimed, _ := arena.Allocate(64)
+		if reclaimed != arena.Memory+secondOffset {
+			t.Errorf("expected next allocation at offset %d, got %d", secondOffset, reclaimed-arena.Memory)
+		}
+	})
+
+	t.Run("deeper checkpoints are invalidated", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 4096))
+		arena.PushPersistentCheckpoint()
+		arena.Allocate(64)
+		arena.PushPersistentCheckpoint()
+		arena.Allocate(64)
+		arena.PushPersistentCheckpoint()
+
+		arena.ResetToCheckpoint(0)
+
+		if err := arena.ResetToCheckpoint(2); err == nil {
+			t.Error("expected error resetting to an invalidated checkpoint")
+		}
+		if index := arena.PushPersistentCheckpoint(); index != 1 {
+			t.Errorf("expected next checkpoint index = 1, got %d", index)
+		}
+	})
+
+	t.Run("returns error for invalid index", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		if err := arena.ResetToCheckpoint(0); err == nil {
+			t.Error("expected error with no checkpoints")
+		}
+		arena.PushPersistentCheckpoint()
+		if err := arena.ResetToCheckpoint(-1); err == nil {
+			t.Error("expected error for negative index")
+		}
+	})
+
+	t.Run("ephemeral reset drops checkpoints above the reset offset", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 4096))
+		arena.Allocate(64)
+		arena.PushPersistentCheckpoint()
+		arena.InitializePersistentMemory()
+		arena.Allocate(64)
+		arena.PushPersistentCheckpoint()
+
+		arena.ResetEphemeralMemory()
+
+		if err := arena.ResetToCheckpoint(1); err == nil {
+			t.Error("expected ephemeral checkpoint to be dropped")
+		}
+		if err := arena.ResetToCheckpoint(0); err != nil {
+			t.Errorf("expected persistent checkpoint to remain, got %v", err)
+		}
+	})
+
+	t.Run("checkpoint below the persistent boundary lowers it", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(4096)
+		checkpoint := arena.PushPersistentCheckpoint()
+		arena.Allocate(64)
+		arena.InitializePersistentMemory()
+
+		arena.ResetToCheckpoint(checkpoint)
+
+		if arena.ArenaResetOffset != arena.NextAllocation {
+			t.Errorf("expected ArenaResetOffset = %d, got %d", arena.NextAllocation, arena.ArenaResetOffset)
+		}
+
+		arena.Allocate(64)
+		arena.Allocate(64)
+		arena.ResetEphemeralMemory()
+		if arena.NextAllocation != 0 {
+			t.Errorf("expected ephemeral reset to reclaim everything after the checkpoint, got NextAllocation = %d", arena.NextAllocation)
+		}
+	})
+}
+
+func TestArena_AllocateOverflow(t *testing.T) {
+	t.Run("size near max uintptr returns ErrCapacityExceeded", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		arena.Allocate(100)
+		before := arena.NextAllocation
+
+		for _, size := range []uintptr{^uintptr(0), ^uintptr(0) - arena.NextAllocation + 1, ^uintptr(0) - 63} {
+			address, err := arena.Allocate(size)
+			if !errors.Is(err, ErrCapacityExceeded) {
+				t.Errorf("size %d: expected ErrCapacityExceeded, got %v", size, err)
+			}
+			if address != 0 {
+				t.Errorf("size %d: expected zero address, got %d", size, address)
+			}
+		}
+		if arena.NextAllocation != before {
+			t.Errorf("expected NextAllocation unchanged at %d, got %d", before, arena.NextAllocation)
+		}
+	})
+
+	t.Run("negative array capacity does not wrap into a valid allocation", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		if _, err := arena.Array_Allocate_Arena(-1, 8); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("allocation after padding overflowed capacity fails cleanly", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 100))
+		arena.Allocate(arena.Capacity - arena.NextAllocation)
+
+		if _, err := arena.Allocate(1); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+// offsetWithAlignment returns the first index into memory whose address has the given
+// remainder modulo alignment, so tests can build slices with a known misalignment.
+func offsetWithAlignment(memory []byte, alignment uintptr, remainder uintptr) int {
+	for i := range memory {
+		if uintptr(unsafe.Pointer(&memory[i]))%alignment == remainder {
+			return i
+		}
+	}
+	panic("no offset with the requested alignment")
+}
+
+func TestNewArena_Validation(t *testing.T) {
+	t.Run("nil memory is treated as empty", func(t *testing.T) {
+		arena, err := NewArena(nil)
+		if !errors.Is(err, ErrEmptyMemory) {
+			t.Fatalf("expected ErrEmptyMemory for nil memory, got %v", err)
+		}
+		if arena != nil {
+			t.Errorf("expected nil arena, got %v", arena)
+		}
+	})
+
+	t.Run("1-byte aligned input is usable", func(t *testing.T) {
+		backing := make([]byte, 256)
+		offset := offsetWithAlignment(backing, 64, 0)
+
+		arena, err := NewArena(backing[offset : offset+1])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arena.NextAllocation != 0 {
+			t.Errorf("expected NextAllocation = 0, got %d", arena.NextAllocation)
+		}
+		if _, err := arena.Allocate(1); err != nil {
+			t.Errorf("expected 1-byte allocation to succeed, got %v", err)
+		}
+	})
+
+	t.Run("1-byte misaligned input returns ErrArenaTooSmall", func(t *testing.T) {
+		backing := make([]byte, 256)
+		offset := offsetWithAlignment(backing, 64, 1)
+
+		arena, err := NewArena(backing[offset : offset+1])
+		if !errors.Is(err, ErrArenaTooSmall) {
+			t.Fatalf("expected ErrArenaTooSmall, got %v", err)
+		}
+		if arena != nil {
+			t.Errorf("expected nil arena, got %v", arena)
+		}
+	})
+
+	t.Run("input smaller than the alignment padding returns ErrArenaTooSmall", func(t *testing.T) {
+		backing := make([]byte, 256)
+		offset := offsetWithAlignment(backing, 64, 16)
+
+		// 48 bytes of padding are needed, only 32 are available
+		if _, err := NewArena(backing[offset : offset+32]); !errors.Is(err, ErrArenaTooSmall) {
+			t.Errorf("expected ErrArenaTooSmall, got %v", err)
+		}
+	})
+
+	t.Run("misaligned input starts at the first aligned address", func(t *testing.T) {
+		backing := make([]byte, 512)
+		offset := offsetWithAlignment(backing, 64, 16)
+
+		arena, err := NewArena(backing[offset : offset+256])
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arena.NextAllocation != 48 {
+			t.Errorf("expected NextAllocation = 48, got %d", arena.NextAllocation)
+		}
+		if arena.NextAllocation > arena.Capacity {
+			t.Errorf("expected NextAllocation <= Capacity, got %d > %d", arena.NextAllocation, arena.Capacity)
+		}
+
+		first, _ := arena.Allocate(10)
+		second, _ := arena.Allocate(10)
+		if first%64 != 0 || second%64 != 0 {
+			t.Errorf("expected cache-line aligned addresses, got %d and %d", first%64, second%64)
+		}
+	})
+
+	t.Run("NewArenaWithSize returns a fully usable aligned block", func(t *testing.T) {
+		for _, size := range []int{1, 40, 100, 1000} {
+			arena, err := NewArenaWithSize(size)
+			if err != nil {
+				t.Fatalf("size %d: expected no error, got %v", size, err)
+			}
+			if arena.Capacity != uintptr(size) {
+				t.Errorf("size %d: expected Capacity = %d, got %d", size, size, arena.Capacity)
+			}
+			if arena.Memory%64 != 0 || arena.NextAllocation != 0 {
+				t.Errorf("size %d: expected aligned memory with no padding, got Memory%%64 = %d, NextAllocation = %d", size, arena.Memory%64, arena.NextAllocation)
+			}
+		}
+	})
+
+	t.Run("rejects cache line size that is not a power of two", func(t *testing.T) {
+		for _, size := range []uintptr{0, 3, 48} {
+			if _, err := NewArena(make([]byte, 1024), ArenaWithCacheLineSize(size)); err == nil {
+				t.Errorf("expected error for cache line size %d", size)
+			}
+		}
+	})
+}
+
+func TestArena_Reset(t *testing.T) {
+	t.Run("clears persistent and ephemeral memory", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		start := arena.NextAllocation
+
+		arena.Allocate(100)
+		arena.InitializePersistentMemory()
+		arena.Allocate(200)
+
+		arena.Reset()
+
+		if arena.NextAllocation != start {
+			t.Errorf("expected NextAllocation = %d, got %d", start, arena.NextAllocation)
+		}
+		if arena.ArenaResetOffset != start {
+			t.Errorf("expected ArenaResetOffset = %d, got %d", start, arena.ArenaResetOffset)
+		}
+
+		address, err := arena.Allocate(50)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address != arena.Memory+start {
+			t.Errorf("expected allocation at the beginning, got offset %d", address-arena.Memory)
+		}
+	})
+
+	t.Run("keeps the initial alignment offset", func(t *testing.T) {
+		backing := make([]byte, 512)
+		offset := offsetWithAlignment(backing, 64, 16)
+		arena, _ := NewArena(backing[offset : offset+256])
+
+		arena.Allocate(10)
+		arena.Reset()
+
+		if arena.NextAllocation != 48 || arena.ArenaResetOffset != 48 {
+			t.Errorf("expected offsets = 48, got NextAllocation = %d, ArenaResetOffset = %d", arena.NextAllocation, arena.ArenaResetOffset)
+		}
+	})
+
+	t.Run("drops checkpoints", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		arena.PushPersistentCheckpoint()
+
+		arena.Reset()
+
+		if err := arena.ResetToCheckpoint(0); err == nil {
+			t.Error("expected checkpoints to be cleared")
+		}
+	})
+}
+
+func TestCopyStruct(t *testing.T) {
+	type point struct {
+		X int64
+		Y int64
+	}
+
+	t.Run("copies fields into the arena", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		p, err := CopyStruct(arena, point{X: 1, Y: 2})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if p.X != 1 || p.Y != 2 {
+			t.Errorf("expected {1 2}, got %+v", *p)
+		}
+
+		address := uintptr(unsafe.Pointer(p))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Error("expected copy to live in arena memory")
+		}
+	})
+
+	t.Run("mutating the original does not affect the copy", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		original := point{X: 1, Y: 2}
+
+		p, _ := CopyStruct(arena, original)
+		original.X = 100
+
+		if p.X != 1 {
+			t.Errorf("expected arena copy X = 1, got %d", p.X)
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(60)
+
+		if _, err := CopyStruct(arena, point{}); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+func TestArena_DataStart(t *testing.T) {
+	t.Run("equals the initial NextAllocation", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		if arena.DataStart() != arena.NextAllocation {
+			t.Errorf("expected DataStart = %d, got %d", arena.NextAllocation, arena.DataStart())
+		}
+		if arena.DataStart() != arena.ArenaResetOffset {
+			t.Errorf("expected DataStart = ArenaResetOffset %d, got %d", arena.ArenaResetOffset, arena.DataStart())
+		}
+		if (arena.Memory+arena.DataStart())%64 != 0 {
+			t.Error("expected DataStart to be cache-line aligned")
+		}
+	})
+
+	t.Run("is stable across resets", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		start := arena.DataStart()
+
+		arena.Allocate(100)
+		arena.InitializePersistentMemory()
+		arena.Allocate(100)
+		arena.ResetEphemeralMemory()
+		if arena.DataStart() != start {
+			t.Errorf("expected DataStart %d after ResetEphemeralMemory, got %d", start, arena.DataStart())
+		}
+
+		arena.Reset()
+		if arena.DataStart() != start {
+			t.Errorf("expected DataStart %d after Reset, got %d", start, arena.DataStart())
+		}
+		if arena.NextAllocation != start {
+			t.Errorf("expected NextAllocation %d after Reset, got %d", start, arena.NextAllocation)
+		}
+	})
+
+	t.Run("is zero for aligned arenas", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		if arena.DataStart() != 0 {
+			t.Errorf("expected DataStart = 0, got %d", arena.DataStart())
+		}
+	})
+}
+
+func TestAllocateStructArray(t *testing.T) {
+	type particle struct {
+		ID       int64
+		Next     *int64
+		Position float32
+	}
+
+	t.Run("allocates a writable aligned array", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		particles, err := AllocateStructArray[particle](arena, 10)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(particles) != 10 {
+			t.Fatalf("expected length 10, got %d", len(particles))
+		}
+
+		first := uintptr(unsafe.Pointer(&particles[0]))
+		if first%unsafe.Alignof(particle{}) != 0 {
+			t.Errorf("expected element 0 aligned to %d, got address %d", unsafe.Alignof(particle{}), first)
+		}
+		if first < arena.Memory || first+10*unsafe.Sizeof(particle{}) > arena.Memory+arena.Capacity {
+			t.Error("expected array to live in arena memory")
+		}
+
+		for i := range particles {
+			particles[i] = particle{ID: int64(i), Next: &particles[i].ID, Position: float32(i) / 2}
+		}
+		for i, p := range particles {
+			if p.ID != int64(i) || *p.Next != int64(i) || p.Position != float32(i)/2 {
+				t.Errorf("element %d: unexpected value %+v", i, p)
+			}
+		}
+	})
+
+	t.Run("returns zeroed memory after reuse", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		dirty, _ := AllocateStructArray[particle](arena, 4)
+		for i := range dirty {
+			dirty[i].ID = 99
+		}
+		arena.ResetEphemeralMemory()
+
+		particles, _ := AllocateStructArray[particle](arena, 4)
+		for i, p := range particles {
+			if p != (particle{}) {
+				t.Errorf("element %d: expected zero value, got %+v", i, p)
+			}
+		}
+	})
+
+	t.Run("zero length returns an empty slice", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		particles, err := AllocateStructArray[particle](arena, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(particles) != 0 {
+			t.Errorf("expected empty slice, got length %d", len(particles))
+		}
+	})
+
+	t.Run("negative length returns ErrInvalidSize", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		if _, err := AllocateStructArray[particle](arena, -1); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("expected ErrInvalidSize, got %v", err)
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := AllocateStructArray[particle](arena, 10); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+func TestArena_AllocateAligned(t *testing.T) {
+	t.Run("aligns the start to the requested alignment", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.AllocateAligned(3, 1)
+
+		address, err := arena.AllocateAligned(16, 8)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address%8 != 0 {
+			t.Errorf("expected 8-byte aligned address, got %d", address)
+		}
+		if arena.NextAllocation != 24 {
+			t.Errorf("expected NextAllocation = 24 with no tail padding, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("rejects alignments that are not a power of two", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		for _, alignment := range []uintptr{0, 3, 12} {
+			if _, err := arena.AllocateAligned(8, alignment); err == nil {
+				t.Errorf("expected error for alignment %d", alignment)
+			}
+		}
+		if arena.NextAllocation != 0 {
+			t.Errorf("expected NextAllocation unchanged, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.AllocateAligned(60, 1)
+
+		if _, err := arena.AllocateAligned(4, 8); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("Allocate after a packed allocation stays cache-line aligned", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.AllocateAligned(8, 8)
+
+		address, err := arena.Allocate(16)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address%64 != 0 {
+			t.Errorf("expected cache-line aligned address, got offset %d", address-arena.Memory)
+		}
+	})
+}
+
+func TestAllocateStruct_Packing(t *testing.T) {
+	type node struct {
+		Value int64
+		Count int32
+		Flags int32
+	}
+
+	t.Run("back-to-back structs consume n*sizeof(T) without padding", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		size := unsafe.Sizeof(node{})
+		n := 50
+
+		var previous *node
+		for i := 0; i < n; i++ {
+			p, err := AllocateStruct[node](arena)
+			if err != nil {
+				t.Fatalf("allocation %d: expected no error, got %v", i, err)
+			}
+			if uintptr(unsafe.Pointer(p))%unsafe.Alignof(node{}) != 0 {
+				t.Errorf("allocation %d: expected aligned address", i)
+			}
+			if previous != nil && uintptr(unsafe.Pointer(p))-uintptr(unsafe.Pointer(previous)) != size {
+				t.Errorf("allocation %d: expected no gap after the previous struct", i)
+			}
+			previous = p
+		}
+
+		if arena.NextAllocation != uintptr(n)*size {
+			t.Errorf("expected %d consumed bytes, got %d", uintptr(n)*size, arena.NextAllocation)
+		}
+	})
+
+	t.Run("a fixed arena fits exactly capacity/sizeof(T) structs", func(t *testing.T) {
+		size := unsafe.Sizeof(node{})
+		arena := NewArenaWithSizeUnsafe(int(size) * 8)
+
+		for i := 0; i < 8; i++ {
+			if _, err := AllocateStruct[node](arena); err != nil {
+				t.Fatalf("allocation %d: expected no error, got %v", i, err)
+			}
+		}
+		if _, err := AllocateStruct[node](arena); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded for the 9th struct, got %v", err)
+		}
+	})
+
+	t.Run("pads only up to the type alignment", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		AllocateStruct[byte](arena)
+
+		p, _ := AllocateStruct[node](arena)
+
+		if offset := uintptr(unsafe.Pointer(p)) - arena.Memory; offset != unsafe.Alignof(node{}) {
+			t.Errorf("expected struct at offset %d, got %d", unsafe.Alignof(node{}), offset)
+		}
+	})
+}
+
+func TestArena_AllocateBytes(t *testing.T) {
+	t.Run("returns a cache-line aligned slice of arena memory", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		b, err := arena.AllocateBytes(100)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(b) != 100 || cap(b) != 100 {
+			t.Errorf("expected len and cap 100, got %d and %d", len(b), cap(b))
+		}
+		address := uintptr(unsafe.Pointer(&b[0]))
+		if address%64 != 0 || address < arena.Memory || address+100 > arena.Memory+arena.Capacity {
+			t.Error("expected aligned bytes inside the arena")
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := arena.AllocateBytes(65); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+func TestAllocateStruct_ZeroSize(t *testing.T) {
+	type empty struct{}
+
+	t.Run("struct{} does not consume capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(10)
+		used := arena.NextAllocation
+
+		p, err := AllocateStruct[struct{}](arena)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if p == nil {
+			t.Error("expected a non-nil pointer")
+		}
+		if arena.NextAllocation != used {
+			t.Errorf("expected NextAllocation to stay at %d, got %d", used, arena.NextAllocation)
+		}
+	})
+
+	t.Run("zero-field struct succeeds on a full arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+
+		p, err := AllocateStruct[empty](arena)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if p == nil {
+			t.Error("expected a non-nil pointer")
+		}
+		if stats := arena.Stats(); stats.AllocationCount != 1 {
+			t.Errorf("expected zero-size allocation not to be counted, got %d allocations", stats.AllocationCount)
+		}
+	})
+
+	t.Run("arrays of zero-size elements do not consume capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		used := arena.NextAllocation
+
+		items, err := AllocateStructArray[empty](arena, 100)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(items) != 100 {
+			t.Errorf("expected length 100, got %d", len(items))
+		}
+		if arena.NextAllocation != used {
+			t.Errorf("expected NextAllocation to stay at %d, got %d", used, arena.NextAllocation)
+		}
+	})
+}
+
+func TestSizeForStructs(t *testing.T) {
+	type node struct {
+		Value int64
+		Next  *int64
+		Tag   byte
+	}
+
+	t.Run("fits n structs at every base alignment", func(t *testing.T) {
+		n := int32(100)
+		size := SizeForStructs[node](n)
+		backing := make([]byte, int(size)+64)
+
+		for shift := 0; shift < 64; shift++ {
+			arena, err := NewArena(backing[shift : shift+int(size)])
+			if err != nil {
+				t.Fatalf("shift %d: expected no error, got %v", shift, err)
+			}
+			for i := int32(0); i < n; i++ {
+				if _, err := AllocateStruct[node](arena); err != nil {
+					t.Fatalf("shift %d, allocation %d: expected no error, got %v", shift, i, err)
+				}
+			}
+		}
+	})
+
+	t.Run("accounts for packed structs plus start padding", func(t *testing.T) {
+		expected := 10*unsafe.Sizeof(node{}) + 63
+		if size := SizeForStructs[node](10); size != expected {
+			t.Errorf("expected %d, got %d", expected, size)
+		}
+	})
+}
+
+func TestSizeForAllocations(t *testing.T) {
+	t.Run("fits every allocation at every base alignment", func(t *testing.T) {
+		sizes := []uintptr{1, 64, 100, 7, 200}
+		size := SizeForAllocations(sizes...)
+		backing := make([]byte, int(size)+64)
+
+		for shift := 0; shift < 64; shift++ {
+			arena, err := NewArena(backing[shift : shift+int(size)])
+			if err != nil {
+				t.Fatalf("shift %d: expected no error, got %v", shift, err)
+			}
+			for i, s := range sizes {
+				if _, err := arena.Allocate(s); err != nil {
+					t.Fatalf("shift %d, allocation %d: expected no error, got %v", shift, i, err)
+				}
+			}
+		}
+	})
+
+	t.Run("rounds each block to the cache line", func(t *testing.T) {
+		if size := SizeForAllocations(1, 65); size != 64+128+63 {
+			t.Errorf("expected %d, got %d", 64+128+63, size)
+		}
+		if size := SizeForAllocations(); size != 63 {
+			t.Errorf("expected 63 for no allocations, got %d", size)
+		}
+	})
+}
+
+func TestNewArenaHugePageAligned(t *testing.T) {
+	t.Run("data starts on a 2MB boundary", func(t *testing.T) {
+		arena, err := NewArenaHugePageAligned(4 << 20)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if (arena.Memory+arena.DataStart())%(2<<20) != 0 {
+			t.Errorf("expected 2MB-aligned data start, got address %#x", arena.Memory+arena.DataStart())
+		}
+		if arena.DataStart() != 0 || arena.Capacity != 4<<20 {
+			t.Errorf("expected the whole block to be usable, got DataStart %d and Capacity %d", arena.DataStart(), arena.Capacity)
+		}
+	})
+
+	t.Run("first allocation is 2MB-aligned", func(t *testing.T) {
+		arena, _ := NewArenaHugePageAligned(1024)
+
+		address, err := arena.Allocate(16)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address%(2<<20) != 0 {
+			t.Errorf("expected 2MB-aligned address, got %#x", address)
+		}
+	})
+
+	t.Run("returns error for empty size", func(t *testing.T) {
+		if _, err := NewArenaHugePageAligned(0); err == nil {
+			t.Error("expected error for zero size")
+		}
+	})
+}
+
+func TestAllocateStructAligned(t *testing.T) {
+	type counter struct {
+		Value int64
+	}
+
+	t.Run("aligns to a 64-byte boundary", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		AllocateStruct[byte](arena)
+
+		p, err := AllocateStructAligned[counter](arena, 64)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if uintptr(unsafe.Pointer(p))%64 != 0 {
+			t.Errorf("expected 64-byte aligned address, got %#x", uintptr(unsafe.Pointer(p)))
+		}
+		if p.Value != 0 {
+			t.Errorf("expected zeroed value, got %d", p.Value)
+		}
+	})
+
+	t.Run("consecutive values land on separate cache lines", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+
+		first, _ := AllocateStructAligned[counter](arena, 64)
+		second, _ := AllocateStructAligned[counter](arena, 64)
+
+		if uintptr(unsafe.Pointer(second))-uintptr(unsafe.Pointer(first)) != 64 {
+			t.Error("expected the second value on the next cache line")
+		}
+	})
+
+	t.Run("rejects invalid alignments", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		for _, alignment := range []uintptr{0, 4, 24} {
+			if _, err := AllocateStructAligned[counter](arena, alignment); err == nil {
+				t.Errorf("expected error for alignment %d", alignment)
+			}
+		}
+	})
+
+	t.Run("returns error when capacity exceeded", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		AllocateStruct[byte](arena)
+
+		if _, err := AllocateStructAligned[counter](arena, 64); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+func TestArena_CorruptedFields(t *testing.T) {
+	t.Run("rejects allocations after Capacity grows past the backing memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Capacity = 1 << 20
+
+		if _, err := arena.Allocate(1024); !errors.Is(err, ErrArenaCorrupted) {
+			t.Errorf("expected ErrArenaCorrupted, got %v", err)
+		}
+		if _, err := AllocateStruct[int64](arena); !errors.Is(err, ErrArenaCorrupted) {
+			t.Errorf("expected ErrArenaCorrupted from AllocateStruct, got %v", err)
+		}
+	})
+
+	t.Run("byte views never read past the backing memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+		arena.Capacity = 1 << 20
+		arena.NextAllocation = 1 << 19
+
+		if len(arena.UsedBytes()) != 64 {
+			t.Errorf("expected UsedBytes to stop at the 64-byte block, got %d bytes", len(arena.UsedBytes()))
+		}
+		arena.Checksum()
+		if _, err := arena.WriteTo(io.Discard); !errors.Is(err, ErrArenaCorrupted) {
+			t.Errorf("expected ErrArenaCorrupted from WriteTo, got %v", err)
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected At past the backing memory to panic")
+			}
+		}()
+		arena.At(4096, 16)
+	})
+
+	t.Run("rejects allocations after Memory is moved", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Memory += 8
+
+		if _, err := arena.Allocate(16); !errors.Is(err, ErrArenaCorrupted) {
+			t.Errorf("expected ErrArenaCorrupted, got %v", err)
+		}
+	})
+
+	t.Run("allows shrinking Capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Capacity = 128
+
+		if _, err := arena.Allocate(64); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		if _, err := arena.Allocate(128); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
+
+func TestMustAllocateStruct(t *testing.T) {
+	type point struct {
+		X, Y int64
+	}
+
+	t.Run("returns a usable pointer", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		p := MustAllocateStruct[point](arena)
+		p.X = 1
+
+		if p.X != 1 || p.Y != 0 {
+			t.Errorf("expected {1 0}, got %+v", *p)
+		}
+	})
+
+	t.Run("panics with a descriptive message on overflow", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+
+		defer func() {
+			message, _ := recover().(string)
+			expected := "MustAllocateStruct[mem.point] of 16 bytes failed: " + ErrCapacityExceeded.Error()
+			if !strings.HasPrefix(message, expected) {
+				t.Errorf("expected panic starting with %q, got %q", expected, message)
+			}
+		}()
+		MustAllocateStruct[point](arena)
+	})
+}
+
+func TestArena_MustAllocate(t *testing.T) {
+	t.Run("returns the address on success", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		if address := arena.MustAllocate(16); address != arena.Memory {
+			t.Errorf("expected address %d, got %d", arena.Memory, address)
+		}
+	})
+
+	t.Run("panics with a descriptive message on overflow", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		defer func() {
+			r := recover()
+			expected := "Arena.MustAllocate of 128 bytes failed: " + ErrCapacityExceeded.Error()
+			if r != expected {
+				t.Errorf("expected panic %q, got %v", expected, r)
+			}
+		}()
+		arena.MustAllocate(128)
+	})
+}
+
+func TestArena_AllocateZeroSize(t *testing.T) {
+	t.Run("Allocate(0) succeeds without advancing", func(t *testing.T) {
+		arena, _ := NewArena(make([]byte, 1024))
+		arena.Allocate(10)
+		before := arena.NextAllocation
+
+		address, err := arena.Allocate(0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address != arena.Memory+before {
+			t.Errorf("expected the next allocation address %d, got %d", arena.Memory+before, address)
+		}
+		if arena.NextAllocation != before {
+			t.Errorf("expected NextAllocation to stay at %d, got %d", before, arena.NextAllocation)
+		}
+		if stats := arena.Stats(); stats.AllocationCount != 1 {
+			t.Errorf("expected zero-size allocation not to be counted, got %d", stats.AllocationCount)
+		}
+	})
+
+	t.Run("AllocateAligned(0) does not advance to the alignment", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.AllocateAligned(3, 1)
+
+		address, err := arena.AllocateAligned(0, 8)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address != arena.Memory+8 {
+			t.Errorf("expected the aligned next address %d, got %d", arena.Memory+8, address)
+		}
+		if arena.NextAllocation != 3 {
+			t.Errorf("expected NextAllocation to stay at 3, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("AllocateBytes(0) returns an empty non-nil slice", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		b, err := arena.AllocateBytes(0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if b == nil || len(b) != 0 {
+			t.Errorf("expected an empty non-nil slice, got %v (nil: %v)", b, b == nil)
+		}
+		if arena.NextAllocation != 0 {
+			t.Errorf("expected NextAllocation to stay at 0, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("zero size succeeds on a full arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(60)
+
+		address, err := arena.Allocate(0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address != arena.Memory+arena.Capacity {
+			t.Errorf("expected the end of the arena, got offset %d", address-arena.Memory)
+		}
+	})
+
+	t.Run("negative element counts are rejected", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := AllocateStructArray[int64](arena, -5); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("expected ErrInvalidSize, got %v", err)
+		}
+	})
+}
+
+func TestArena_AllocateSIMD(t *testing.T) {
+	allocators := []struct {
+		name      string
+		alignment uintptr
+		allocate  func(*Arena, uintptr) ([]byte, error)
+	}{
+		{"AllocateSIMD16", 16, (*Arena).AllocateSIMD16},
+		{"AllocateSIMD32", 32, (*Arena).AllocateSIMD32},
+	}
+
+	for _, allocator := range allocators {
+		t.Run(allocator.name+" aligns the data pointer", func(t *testing.T) {
+			arena := NewArenaWithSizeUnsafe(1024)
+			arena.AllocateAligned(3, 1)
+
+			for i := 0; i < 3; i++ {
+				b, err := allocator.allocate(arena, 40)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(b) != 40 || cap(b) != 40 {
+					t.Errorf("expected len and cap 40, got %d and %d", len(b), cap(b))
+				}
+				if address := uintptr(unsafe.Pointer(&b[0])); address%allocator.alignment != 0 {
+					t.Errorf("expected %d-byte aligned data pointer, got %#x", allocator.alignment, address)
+				}
+			}
+		})
+
+		t.Run(allocator.name+" returns error when capacity exceeded", func(t *testing.T) {
+			arena := NewArenaWithSizeUnsafe(64)
+
+			if _, err := allocator.allocate(arena, 65); !errors.Is(err, ErrCapacityExceeded) {
+				t.Errorf("expected ErrCapacityExceeded, got %v", err)
+			}
+		})
+
+		t.Run(allocator.name+" zero size returns an empty slice", func(t *testing.T) {
+			arena := NewArenaWithSizeUnsafe(64)
+
+			b, err := allocator.allocate(arena, 0)
+			if err != nil || b == nil || len(b) != 0 {
+				t.Errorf("expected empty non-nil slice, got %v, %v", b, err)
+			}
+		})
+	}
+}
+
+func TestArenaWithDefaultAlignment(t *testing.T) {
+	t.Run("aligns consecutive Allocate calls to the default alignment", func(t *testing.T) {
+		arena, err := NewArena(alignedMemory(1024, 64), ArenaWithDefaultAlignment(16))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		first, _ := arena.Allocate(3)
+		second, _ := arena.Allocate(20)
+		third, _ := arena.Allocate(16)
+
+		for _, address := range []uintptr{first, second, third} {
+			if address%16 != 0 {
+				t.Errorf("expected 16-byte aligned address, got %#x", address)
+			}
+		}
+		if second-first != 16 || third-second != 32 {
+			t.Errorf("expected blocks padded to 16 bytes, got gaps %d and %d", second-first, third-second)
+		}
+		if arena.NextAllocation != 64 {
+			t.Errorf("expected NextAllocation = 64, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("defaults to the cache line size", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arena.Allocate(3)
+
+		if arena.NextAllocation != 64 {
+			t.Errorf("expected NextAllocation = 64, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("child arenas inherit the default alignment", func(t *testing.T) {
+		arena, _ := NewArena(alignedMemory(1024, 64), ArenaWithDefaultAlignment(8))
+		child, err := arena.CreateChild(256)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		child.Allocate(3)
+
+		if child.NextAllocation != 8 {
+			t.Errorf("expected NextAllocation = 8, got %d", child.NextAllocation)
+		}
+	})
+
+	t.Run("rejects alignments that are not a power of two", func(t *testing.T) {
+		if _, err := NewArena(alignedMemory(1024, 64), ArenaWithDefaultAlignment(12)); err == nil {
+			t.Error("expected error for alignment 12")
+		}
+	})
+}
+
+func TestArena_Remaining(t *testing.T) {
+	t.Run("counts the bytes after NextAllocation", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(256)
+		if arena.Remaining() != 256 {
+			t.Errorf("expected 256, got %d", arena.Remaining())
+		}
+
+		arena.AllocateAligned(10, 1)
+		if arena.Remaining() != 246 {
+			t.Errorf("expected 246, got %d", arena.Remaining())
+		}
+
+		arena.AllocateAligned(246, 1)
+		if arena.Remaining() != 0 {
+			t.Errorf("expected 0, got %d", arena.Remaining())
+		}
+	})
+}
+
+func TestArena_AlignedRemaining(t *testing.T) {
+	t.Run("subtracts the padding for the alignment", func(t *testing.T) {
+		for _, offset := range []uintptr{0, 1, 7, 8, 13, 100} {
+			for _, alignment := range []uintptr{1, 2, 8, 16, 64} {
+				arena := NewArenaWithSizeUnsafe(256)
+				arena.AllocateAligned(offset, 1)
+
+				remaining := arena.AlignedRemaining(alignment)
+				expected := 256 - (offset+alignment-1)&^(alignment-1)
+				if remaining != expected {
+					t.Errorf("offset %d, alignment %d: expected %d, got %d", offset, alignment, expected, remaining)
+				}
+				if _, err := arena.AllocateAligned(remaining+1, alignment); !errors.Is(err, ErrCapacityExceeded) {
+					t.Errorf("offset %d, alignment %d: expected %d bytes not to fit, got %v", offset, alignment, remaining+1, err)
+				}
+				if _, err := arena.AllocateAligned(remaining, alignment); remaining > 0 && err != nil {
+					t.Errorf("offset %d, alignment %d: expected %d bytes to fit, got %v", offset, alignment, remaining, err)
+				}
+			}
+		}
+	})
+
+	t.Run("returns 0 when the padding alone exceeds the arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.AllocateAligned(60, 1)
+
+		if remaining := arena.AlignedRemaining(64); remaining != 0 {
+			t.Errorf("expected 0, got %d", remaining)
+		}
+	})
+
+	t.Run("returns 0 for alignments that are not a power of two", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		for _, alignment := range []uintptr{0, 3, 12} {
+			if remaining := arena.AlignedRemaining(alignment); remaining != 0 {
+				t.Errorf("alignment %d: expected 0, got %d", alignment, remaining)
+			}
+		}
+	})
+}
+
+func TestArena_AllocateStructPaddingOverflow(t *testing.T) {
+	type block struct{ values [7]int64 }
+
+	t.Run("reports size, padding and remaining bytes when only the padding overflows", func(t *testing.T) {
+		arena, _ := NewArena(alignedMemory(62, 64))
+		arena.AllocateAligned(1, 1)
+
+		_, err := AllocateStruct[block](arena)
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+		}
+		expected := ErrCapacityExceeded.Error() + ": requested 56 bytes plus 7 bytes of alignment padding, 61 bytes remaining"
+		if err.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, err.Error())
+		}
+		if arena.NextAllocation != 1 {
+			t.Errorf("expected NextAllocation unchanged, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("fits when no padding is needed", func(t *testing.T) {
+		arena, _ := NewArena(alignedMemory(62, 64))
+
+		if _, err := AllocateStruct[block](arena); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
+
+func TestArena_UsedBytes(t *testing.T) {
+	t.Run("covers DataStart to NextAllocation", func(t *testing.T) {
+		backing := make([]byte, 1024)
+		arena, _ := NewArena(backing[offsetWithAlignment(backing, 64, 3):])
+
+		first, _ := arena.AllocateBytes(5)
+		copy(first, "hello")
+		second, _ := arena.AllocateAligned(3, 1)
+		copy(arena.bytes()[second-arena.Memory:], "abc")
+
+		used := arena.UsedBytes()
+		if uintptr(len(used)) != arena.NextAllocation-arena.DataStart() {
+			t.Errorf("expected %d bytes, got %d", arena.NextAllocation-arena.DataStart(), len(used))
+		}
+		if string(used[:5]) != "hello" || string(used[64:67]) != "abc" {
+			t.Errorf("expected written contents, got %q and %q", used[:5], used[64:67])
+		}
+		if &used[0] != &first[0] {
+			t.Error("expected the slice to alias arena memory")
+		}
+	})
+
+	t.Run("is empty for a fresh arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if used := arena.UsedBytes(); len(used) != 0 {
+			t.Errorf("expected no used bytes, got %d", len(used))
+		}
+	})
+
+	t.Run("stops at Capacity when NextAllocation overflows it", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(100)
+		arena.Allocate(90)
+
+		if used := arena.UsedBytes(); len(used) != 100 {
+			t.Errorf("expected 100 bytes, got %d", len(used))
+		}
+	})
+}
+
+func TestAllocateStructInit(t *testing.T) {
+	type node struct {
+		id       int32
+		children []int32
+		weight   float64
+	}
+	type point struct{ x, y int32 }
+
+	t.Run("returns the initialized struct", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		n, err := AllocateStructInit(arena, func(n *node) {
+			n.id = 7
+			n.weight = 1.5
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if n.id != 7 || n.weight != 1.5 || n.children != nil {
+			t.Errorf("expected {7 [] 1.5}, got %+v", *n)
+		}
+		address := uintptr(unsafe.Pointer(n))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Error("expected the struct to live in arena memory")
+		}
+	})
+
+	t.Run("init sees zeroed memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		for i := range arena.bytes() {
+			arena.bytes()[i] = 0xff
+		}
+
+		AllocateStructInit(arena, func(p *point) {
+			if *p != (point{}) {
+				t.Errorf("expected zeroed struct, got %+v", *p)
+			}
+		})
+	})
+
+	t.Run("init is skipped when the allocation fails", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arena.Allocate(64)
+
+		called := false
+		p, err := AllocateStructInit(arena, func(*point) { called = true })
+		if !errors.Is(err, ErrCapacityExceeded) || p != nil || called {
+			t.Errorf("expected ErrCapacityExceeded without calling init, got %v, %v, called=%v", p, err, called)
+		}
+	})
+
+	t.Run("nil init behaves like AllocateStruct", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		if p, err := AllocateStructInit[point](arena, nil); err != nil || p == nil {
+			t.Errorf("expected a struct, got %v, %v", p, err)
+		}
+	})
+}
+
+func TestArena_Generation(t *testing.T) {
+	t.Run("increments on every reset", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		if arena.Generation() != 0 {
+			t.Errorf("expected generation 0, got %d", arena.Generation())
+		}
+
+		arena.ResetEphemeralMemory()
+		arena.ResetEphemeralMemory()
+		if arena.Generation() != 2 {
+			t.Errorf("expected generation 2 after two ephemeral resets, got %d", arena.Generation())
+		}
+
+		checkpoint := arena.PushPersistentCheckpoint()
+		arena.ResetToCheckpoint(checkpoint)
+		arena.Reset()
+		if arena.Generation() != 4 {
+			t.Errorf("expected generation 4, got %d", arena.Generation())
+		}
+	})
+
+	t.Run("allocations do not change the generation", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		generation := arena.Generation()
+
+		arena.Allocate(16)
+		AllocateStruct[int64](arena)
+
+		if !arena.Valid(generation) {
+			t.Error("expected generation to remain valid without a reset")
+		}
+	})
+
+	t.Run("a reset invalidates earlier generations", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		generation := arena.Generation()
+
+		arena.ResetEphemeralMemory()
+
+		if arena.Valid(generation) {
+			t.Error("expected the old generation to be invalid after a reset")
+		}
+		if !arena.Valid(arena.Generation()) {
+			t.Error("expected the current generation to be valid")
+		}
+	})
+}
+
+func TestArena_AllocateFromTop(t *testing.T) {
+	t.Run("carves aligned blocks downwards from the end", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(256)
+
+		first, err := arena.AllocateFromTop(10)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		second, _ := arena.AllocateFromTop(64)
+
+		if len(first) != 10 || arena.offsetOf(first) != 192 {
+			t.Errorf("expected 10 bytes at offset 192, got %d at %d", len(first), arena.offsetOf(first))
+		}
+		if len(second) != 64 || arena.offsetOf(second) != 128 {
+			t.Errorf("expected 64 bytes at offset 128, got %d at %d", len(second), arena.offsetOf(second))
+		}
+		if arena.NextAllocation != 0 {
+			t.Errorf("expected NextAllocation unchanged, got %d", arena.NextAllocation)
+		}
+	})
+
+	t.Run("both ends meet in the middle", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(256)
+
+		arena.AllocateFromTop(64)
+		arena.Allocate(64)
+		arena.AllocateFromTop(64)
+		arena.Allocate(64)
+
+		if arena.Remaining() != 0 {
+			t.Errorf("expected no room left, got %d", arena.Remaining())
+		}
+		if _, err := arena.Allocate(1); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected bottom allocation to fail with ErrCapacityExceeded, got %v", err)
+		}
+		if _, err := arena.AllocateFromTop(1); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected top allocation to fail with ErrCapacityExceeded, got %v", err)
+		}
+	})
+
+	t.Run("bottom allocations stop at the top blocks", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(256)
+		arena.AllocateFromTop(100)
+
+		if _, err := arena.Allocate(129); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if _, err := arena.Allocate(128); err != nil {
+			t.Errorf("expected the space below the top blocks to fit, got %v", err)
+		}
+	})
+
+	t.Run("resets release the top blocks", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(256)
+		arena.AllocateFromTop(200)
+
+		arena.ResetEphemeralMemory()
+		if _, err := arena.Allocate(256); err != nil {
+			t.Errorf("expected the full arena after ResetEphemeralMemory, got %v", err)
+		}
+
+		arena.Reset()
+		checkpoint := arena.PushPersistentCheckpoint()
+		arena.AllocateFromTop(200)
+		arena.ResetToCheckpoint(checkpoint)
+		if _, err := arena.Allocate(256); err != nil {
+			t.Errorf("expected the full arena after ResetToCheckpoint, got %v", err)
+		}
+
+		arena.Reset()
+		if top, err := arena.AllocateFromTop(256); err != nil || arena.offsetOf(top) != 0 {
+			t.Errorf("expected the full arena after Reset, got %v", err)
+		}
+	})
+
+	t.Run("zero and oversized requests", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if b, err := arena.AllocateFromTop(0); err != nil || b == nil || len(b) != 0 {
+			t.Errorf("expected empty non-nil slice, got %v, %v", b, err)
+		}
+		if _, err := arena.AllocateFromTop(65); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package mem
+
+import "io"
+
+// ArenaWriter is an io.Writer that appends into a block reserved from an arena, so
+// fmt.Fprintf or encoders can write into arena memory without a heap-allocated buffer.
+// Overflowing writes are partial, as io.Writer allows, so a byte stream keeps as much output
+// as fits. ArenaStringBuilder builds on it but rejects overflowing writes as a whole instead,
+// because a truncated string could end in the middle of a UTF-8 sequence or a value.
+type ArenaWriter struct {
+	buffer []byte
+}
+
+var (
+	_ io.Writer       = (*ArenaWriter)(nil)
+	_ io.StringWriter = (*ArenaWriter)(nil)
+)
+
+// Writer reserves maxSize bytes from the arena and returns a writer appending into them.
+func (a *Arena) Writer(maxSize uintptr) (*ArenaWriter, error) {
+	block, err := a.AllocateBytes(maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ArenaWriter{buffer: block[:0]}, nil
+}
+
+// Write appends p to the reserved block. If p does not fit, as much as fits is written
+// and ErrCapacityExceeded is returned.
+func (w *ArenaWriter) Write(p []byte) (int, error) {
+	available := w.Available()
+	if len(p) > available {
+		w.buffer = append(w.buffer, p[:available]...)
+		return available, ErrCapacityExceeded
+	}
+	w.buffer = append(w.buffer, p...)
+	return len(p), nil
+}
+
+// WriteString is like Write for a string, without converting it to a byte slice.
+func (w *ArenaWriter) WriteString(s string) (int, error) {
+	available := w.Available()
+	if len(s) > available {
+		w.buffer = append(w.buffer, s[:available]...)
+		return available, ErrCapacityExceeded
+	}
+	w.buffer = append(w.buffer, s...)
+	return len(s), nil
+}
+
+// Bytes returns the bytes written so far. The slice aliases arena memory.
+func (w *ArenaWriter) Bytes() []byte {
+	return w.buffer
+}
+
+// Len returns the number of bytes written so far.
+func (w *ArenaWriter) Len() int {
+	return len(w.buffer)
+}
+
+// Available returns the number of bytes that can still be written.
+func (w *ArenaWriter) Available() int {
+	return cap(w.buffer) - len(w.buffer)
+}
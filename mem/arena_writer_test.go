@@ -0,0 +1,95 @@
+package mem
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"unsafe"
+)
+
+func TestArena_Writer(t *testing.T) {
+	t.Run("writes formatted output into arena memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		writer, err := arena.Writer(64)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		fmt.Fprintf(writer, "node %d at (%.1f, %.1f)", 7, 1.5, 2.5)
+
+		if got := string(writer.Bytes()); got != "node 7 at (1.5, 2.5)" {
+			t.Errorf("expected %q, got %q", "node 7 at (1.5, 2.5)", got)
+		}
+		if writer.Len() != len("node 7 at (1.5, 2.5)") {
+			t.Errorf("expected Len %d, got %d", len("node 7 at (1.5, 2.5)"), writer.Len())
+		}
+		address := uintptr(unsafe.Pointer(&writer.Bytes()[0]))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Error("expected written bytes to live in arena memory")
+		}
+	})
+
+	t.Run("consecutive writes append", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		writer, _ := arena.Writer(16)
+
+		writer.Write([]byte("ab"))
+		writer.Write([]byte("cd"))
+
+		if got := string(writer.Bytes()); got != "abcd" {
+			t.Errorf("expected %q, got %q", "abcd", got)
+		}
+	})
+
+	t.Run("writes beyond the reserved size return an error", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		writer, _ := arena.Writer(4)
+
+		n, err := writer.Write([]byte("hello"))
+
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if n != 4 || string(writer.Bytes()) != "hell" {
+			t.Errorf("expected the first 4 bytes to be written, got %d bytes %q", n, writer.Bytes())
+		}
+		if _, err := writer.Write([]byte("x")); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded on a full writer, got %v", err)
+		}
+	})
+
+	t.Run("WriteString writes partially like Write", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		writer, _ := arena.Writer(4)
+
+		n, err := writer.WriteString("hello")
+
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if n != 4 || string(writer.Bytes()) != "hell" || writer.Available() != 0 {
+			t.Errorf("expected the first 4 bytes to be written, got %d bytes %q", n, writer.Bytes())
+		}
+	})
+
+	t.Run("does not write into the following allocation", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		writer, _ := arena.Writer(4)
+		next, _ := arena.AllocateBytes(4)
+		copy(next, "next")
+
+		writer.Write([]byte("overflow"))
+
+		if string(next) != "next" {
+			t.Errorf("expected the next allocation to be untouched, got %q", next)
+		}
+	})
+
+	t.Run("returns error when the reservation does not fit", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := arena.Writer(128); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
@@ -2,15 +2,23 @@ package mem
 
 import (
 	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"sort"
 	"unsafe"
 )
 
+// MemArray is a fixed-capacity array. Length and Capacity are derived from the unexported
+// internalArray (len and cap), not stored separately, so they cannot disagree with the backing
+// storage: every guard, including Add's capacity check, reads the same slice it writes to.
 type MemArray[T any] struct {
 	isHashmap       bool
 	ZeroValue       T
 	ZeroValuePtr    *T
 	internalArray   []T
 	hashInitialised bool
+	growable        bool
 }
 
 func (m *MemArray[T]) Length() int32 {
@@ -31,7 +39,16 @@ func (m *MemArray[T]) Capacity() int32 {
 	return int32(cap(m.internalArray))
 }
 
+// Add appends item and returns a pointer to it. A full array panics, unless it was created with
+// MemArrayWithGrowable, in which case it grows like AddGrowing.
 func (m *MemArray[T]) Add(item T) *T {
+	if m.growable {
+		added, err := m.AddGrowing(item)
+		if err != nil {
+			panic(fmt.Sprintf("MemArray.Add %v", err))
+		}
+		return added
+	}
 	if m.isFull() {
 		panic(fmt.Sprintf("MemArray.Add capacity exceeded: %d + 1 > %d", m.Length(), m.Capacity()))
 	}
@@ -40,6 +57,59 @@ func (m *MemArray[T]) Add(item T) *T {
 	return &m.internalArray[m.Length()-1]
 }
 
+// AddGrowing appends item like Add, but when the array is full it first doubles the capacity
+// (reallocating the backing slice on the Go heap) instead of panicking. Growing invalidates
+// pointers previously obtained via Get/Add, as with GrowCapacity. The capacity is clamped to
+// math.MaxInt32; it returns an error wrapping ErrCapacityExceeded once that is reached.
+func (m *MemArray[T]) AddGrowing(item T) (*T, error) {
+	if m.isFull() {
+		if err := m.growFor(1); err != nil {
+			return nil, err
+		}
+	}
+	m.internalArray = append(m.internalArray, item)
+	return &m.internalArray[m.Length()-1], nil
+}
+
+// growFor grows the capacity so that needed more elements fit, at least doubling it.
+func (m *MemArray[T]) growFor(needed int) error {
+	newCapacity, err := grownCapacity(int(m.Capacity()), int(m.Length())+needed)
+	if err != nil {
+		return err
+	}
+	return m.GrowCapacity(newCapacity)
+}
+
+// grownCapacity doubles capacity (at least to required), computed in int so it cannot
+// overflow, and clamps the result to math.MaxInt32.
+func grownCapacity(capacity int, required int) (int32, error) {
+	if required > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %d elements exceed the maximum MemArray capacity %d", ErrCapacityExceeded, required, math.MaxInt32)
+	}
+	return int32(min(max(2*capacity, required, 1), math.MaxInt32)), nil
+}
+
+// AppendCopy copies src onto the end of the array with a single capacity check and returns
+// the number of elements appended. When src does not fit, as many elements as fit are copied
+// and ErrCapacityExceeded is returned; growable arrays grow to fit instead, returning the
+// growth error if they cannot.
+func (m *MemArray[T]) AppendCopy(src []T) (int32, error) {
+	free := int(m.Capacity() - m.Length())
+	if len(src) > free && m.growable {
+		if err := m.growFor(len(src)); err != nil {
+			return 0, err
+		}
+		free = len(src)
+	}
+	length := int(m.Length())
+	n := copy(m.internalArray[length:length+min(len(src), free)], src)
+	m.internalArray = m.internalArray[:length+n]
+	if n < len(src) {
+		return int32(n), fmt.Errorf("%w: appended %d of %d elements", ErrCapacityExceeded, n, len(src))
+	}
+	return int32(n), nil
+}
+
 func (m *MemArray[T]) Get(index int32) *T {
 
 	if !rangeCheck(index, m.Length()) {
@@ -99,19 +169,110 @@ func (m *MemArray[T]) GetValue(index int32) T {
 	return internalArray[index]
 }
 
-func (m *MemArray[T]) RemoveSwapback(index int32) T {
+// GetWrapped returns a pointer to the element at index modulo Length, so any index, including
+// a negative one, maps into [0, Length), e.g. -1 is the last element. It returns nil for an
+// empty array.
+func (m *MemArray[T]) GetWrapped(index int32) *T {
+	length := m.Length()
+	if length == 0 {
+		return nil
+	}
+	index %= length
+	if index < 0 {
+		index += length
+	}
+	return &m.internalArray[index]
+}
+
+// GetOr returns the value at index, or def if index is outside [0, Length).
+func (m *MemArray[T]) GetOr(index int32, def T) T {
 	if !rangeCheck(index, m.Length()) {
+		return def
+	}
+	return m.internalArray[index]
+}
+
+func (m *MemArray[T]) RemoveSwapback(index int32) T {
+	removed, ok := m.TryRemoveSwapback(index)
+	if !ok {
 		message := fmt.Sprintf("MemArray.RemoveSwapback index out of bounds: %d, length: %d\n", index, m.Length())
 		panic(message)
 	}
+	return removed
+}
+
+func (m *MemArray[T]) TryRemoveSwapback(index int32) (T, bool) {
+	if !rangeCheck(index, m.Length()) {
+		var zero T
+		return zero, false
+	}
 
 	removed := m.internalArray[index]
 	m.internalArray[index] = m.internalArray[m.Length()-1]
 	m.internalArray = m.internalArray[:m.Length()-1]
 
+	return removed, true
+}
+
+func (m *MemArray[T]) Pop() (T, bool) {
+	if m.Length() == 0 {
+		var zero T
+		return zero, false
+	}
+	removed := m.internalArray[m.Length()-1]
+	m.internalArray = m.internalArray[:m.Length()-1]
+	return removed, true
+}
+
+// RemoveValueFunc removes the first element matching match with swapback, so the order of
+// the remaining elements is not preserved. It reports whether an element was removed.
+func (m *MemArray[T]) RemoveValueFunc(match func(T) bool) bool {
+	for i, item := range m.internalArray {
+		if match(item) {
+			m.TryRemoveSwapback(int32(i))
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemArray[T]) RemoveAll(drop func(T) bool) int32 {
+	kept := int32(0)
+	for _, item := range m.internalArray {
+		if !drop(item) {
+			m.internalArray[kept] = item
+			kept++
+		}
+	}
+	removed := m.Length() - kept
+	m.internalArray = m.internalArray[:kept]
 	return removed
 }
 
+func (m *MemArray[T]) Swap(i int32, j int32) bool {
+	if !rangeCheck(i, m.Length()) || !rangeCheck(j, m.Length()) {
+		return false
+	}
+	m.internalArray[i], m.internalArray[j] = m.internalArray[j], m.internalArray[i]
+	return true
+}
+
+func (m *MemArray[T]) Reverse() {
+	for i, j := int32(0), m.Length()-1; i < j; i, j = i+1, j-1 {
+		m.internalArray[i], m.internalArray[j] = m.internalArray[j], m.internalArray[i]
+	}
+}
+
+func (m *MemArray[T]) Fill(value T, count int32) {
+	count = max(0, min(count, m.Capacity()))
+	if count > m.Length() {
+		m.internalArray = m.internalArray[:count]
+	}
+	for i := int32(0); i < count; i++ {
+		m.internalArray[i] = value
+	}
+}
+
 func (m *MemArray[T]) Reset() {
 	if m.isHashmap {
 		for i := int32(0); i < m.Capacity()-2; i++ {
@@ -130,6 +291,18 @@ func (m *MemArray[T]) Shrink(length int32) {
 	m.internalArray = m.internalArray[:m.Length()-length]
 }
 
+// Truncate drops the elements from newLength on, keeping the capacity. It reports false and
+// leaves the array unchanged unless 0 <= newLength <= Length. The dropped elements are zeroed
+// so they no longer keep referenced values alive.
+func (m *MemArray[T]) Truncate(newLength int32) bool {
+	if newLength < 0 || newLength > m.Length() {
+		return false
+	}
+	clear(m.internalArray[newLength:])
+	m.internalArray = m.internalArray[:newLength]
+	return true
+}
+
 func (m *MemArray[T]) Grow(length int32) {
 	if m.Length()+length > m.Capacity() {
 		panic(fmt.Sprintf("MemArray.Grow capacity exceeded: %d + %d > %d", m.Length(), length, m.Capacity()))
@@ -137,6 +310,73 @@ func (m *MemArray[T]) Grow(length int32) {
 	m.internalArray = m.internalArray[:m.Length()+length]
 }
 
+// GrowCapacity moves the array into a new backing slice of newCapacity, copying [0, Length).
+// Pointers previously obtained via Get/Add point into the old backing slice and are
+// no longer valid after a successful call.
+func (m *MemArray[T]) GrowCapacity(newCapacity int32) error {
+	if newCapacity < m.Length() {
+		return fmt.Errorf("MemArray.GrowCapacity new capacity is less than the array length: %d < %d", newCapacity, m.Length())
+	}
+	internalArray := make([]T, m.Length(), newCapacity)
+	copy(internalArray, m.internalArray)
+	m.internalArray = internalArray
+	return nil
+}
+
+// Reserve grows the capacity to at least minCapacity, only reallocating if needed.
+func (m *MemArray[T]) Reserve(minCapacity int32) error {
+	if minCapacity <= m.Capacity() {
+		return nil
+	}
+	return m.GrowCapacity(minCapacity)
+}
+
+// GrowCapacityInArena is like GrowCapacity, but carves the new backing block from arena
+// instead of the Go heap. The old block cannot be freed by a bump allocator, so the space
+// it occupied is wasted until the arena is reset; prefer growing ephemeral arrays and
+// reclaiming with ResetEphemeralMemory.
+func (m *MemArray[T]) GrowCapacityInArena(arena *Arena, newCapacity int32) error {
+	if newCapacity < m.Length() {
+		return fmt.Errorf("MemArray.GrowCapacityInArena new capacity is less than the array length: %d < %d", newCapacity, m.Length())
+	}
+	if newCapacity == 0 {
+		m.internalArray = m.internalArray[:0:0]
+		return nil
+	}
+
+	var zero T
+	address, err := arena.AllocateAligned(uintptr(newCapacity)*unsafe.Sizeof(zero), unsafe.Alignof(zero))
+	if err != nil {
+		return err
+	}
+
+	// The block may hold stale bytes from earlier use of the arena; zero the part beyond the
+	// copied elements so it reads like a heap-grown array.
+	block := unsafe.Slice((*T)(arena.pointerAt(address)), newCapacity)
+	clear(block[copy(block, m.internalArray):])
+	m.internalArray = block[:m.Length()]
+	return nil
+}
+
+// CopyFrom replaces the populated region with a copy of src's populated region.
+// Elements are copied by value, so pointers inside T are shared.
+func (m *MemArray[T]) CopyFrom(src *MemArray[T]) error {
+	if m.Capacity() < src.Length() {
+		return fmt.Errorf("MemArray.CopyFrom capacity is less than the source length: %d < %d", m.Capacity(), src.Length())
+	}
+	m.internalArray = m.internalArray[:src.Length()]
+	copy(m.internalArray, src.internalArray)
+	return nil
+}
+
+// Snapshot returns a copy of the populated region, independent of the array's backing memory,
+// so it can be handed to readers in other goroutines. MemArray does no locking of its own:
+// take the snapshot under the same lock the writers hold (e.g. the read side of a
+// sync.RWMutex), then release it before reading the copy.
+func (m *MemArray[T]) Snapshot() []T {
+	return slices.Clone(m.internalArray)
+}
+
 func (m *MemArray[T]) isFull() bool {
 	return m.Length() == m.Capacity()
 }
@@ -151,6 +391,7 @@ type MemArrayOptions[T any] struct {
 	ZeroValue     T
 	ZeroValuePtr  *T
 	InitialLength int32
+	Growable      bool
 }
 type MemArrayOption[T any] func(*MemArrayOptions[T])
 
@@ -160,6 +401,14 @@ func MemArrayWithArena[T any](arena *Arena) MemArrayOption[T] {
 	}
 }
 
+// MemArrayWithGrowable makes Add double the capacity of a full array instead of panicking,
+// for a slice-like experience. Growing invalidates pointers previously obtained via Get/Add.
+func MemArrayWithGrowable[T any]() MemArrayOption[T] {
+	return func(o *MemArrayOptions[T]) {
+		o.Growable = true
+	}
+}
+
 func MemArrayWithIsHashmap[T any]() MemArrayOption[T] {
 	return func(o *MemArrayOptions[T]) {
 		o.IsHashmap = true
@@ -182,10 +431,9 @@ func MemArrayWithInitialLength[T any](length int32) MemArrayOption[T] {
 		o.InitialLength = length
 	}
 }
-func defaultMemArrayOptions[T any](size int) MemArrayOptions[T] {
+func defaultMemArrayOptions[T any]() MemArrayOptions[T] {
 	zero := new(T)
 	return MemArrayOptions[T]{
-		Arena:         NewArenaWithSizeUnsafe(size),
 		IsHashmap:     false,
 		ZeroValue:     *zero,
 		ZeroValuePtr:  zero,
@@ -193,11 +441,12 @@ func defaultMemArrayOptions[T any](size int) MemArrayOptions[T] {
 	}
 }
 
+// NewMemArray creates an array with room for capacity values. A negative capacity is
+// clamped to 0, giving an empty array that cannot hold any values.
 func NewMemArray[T any](capacity int32, options ...MemArrayOption[T]) MemArray[T] {
+	capacity = max(capacity, 0)
 
-	sizeOfArray := int(capacity) * int(unsafe.Sizeof(new(T)))
-
-	opts := defaultMemArrayOptions[T](sizeOfArray)
+	opts := defaultMemArrayOptions[T]()
 	for _, option := range options {
 		option(&opts)
 	}
@@ -207,6 +456,7 @@ func NewMemArray[T any](capacity int32, options ...MemArrayOption[T]) MemArray[T
 		ZeroValue:     opts.ZeroValue,
 		ZeroValuePtr:  opts.ZeroValuePtr,
 		internalArray: make([]T, opts.InitialLength, capacity),
+		growable:      opts.Growable,
 	}
 	if opts.IsHashmap {
 		m.initHashMap()
@@ -214,6 +464,28 @@ func NewMemArray[T any](capacity int32, options ...MemArrayOption[T]) MemArray[T
 	return m
 }
 
+// NewMemArrayFromSlice creates a full array holding a copy of src, with Length == Capacity ==
+// len(src); the inverse of MArray_ToSlice. Later changes to src do not affect the array.
+func NewMemArrayFromSlice[T any](src []T) MemArray[T] {
+	m := NewMemArray[T](int32(len(src)))
+	m.internalArray = append(m.internalArray, src...)
+	return m
+}
+
+// MArray_ReinterpretAs views the populated bytes of src as a full MemArray[T] without copying,
+// with Length == Capacity == src.Length() / sizeof(T); writes through either array are visible
+// in the other. The byte length must be a multiple of the size of T and the bytes must be
+// aligned for T.
+func MArray_ReinterpretAs[T any](src *MemArray[byte]) (MemArray[T], error) {
+	items, err := reinterpretBytes[T](MArray_ToSlice(src))
+	if err != nil {
+		return MemArray[T]{}, err
+	}
+	m := NewMemArray[T](0)
+	m.internalArray = items
+	return m, nil
+}
+
 func rangeCheck(index int32, length int32) bool {
 	return index < length && index >= 0
 }
@@ -233,11 +505,31 @@ func MArray_GetValue[T any](array *MemArray[T], index int32) T {
 	return array.GetValue(index)
 }
 
+// modular indexing for ring-style access, -1 is the last value; nil when length == 0
+func MArray_GetWrapped[T any](array *MemArray[T], index int32) *T {
+	return array.GetWrapped(index)
+}
+
+// returns def instead of panicking when index is outside [0, length)
+func MArray_GetOr[T any](array *MemArray[T], index int32, def T) T {
+	return array.GetOr(index, def)
+}
+
 // can only add new values up to the capacity
 func MArray_Add[T any](array *MemArray[T], item T) *T {
 	return array.Add(item)
 }
 
+// appends, doubling the capacity when full; invalidates previously obtained pointers when it grows
+func MArray_AddGrowing[T any](array *MemArray[T], item T) (*T, error) {
+	return array.AddGrowing(item)
+}
+
+// copies src onto the end with one capacity check; appends what fits and returns ErrCapacityExceeded for the rest
+func MArray_AppendCopy[T any](array *MemArray[T], src []T) (int32, error) {
+	return array.AppendCopy(src)
+}
+
 // can only overwrite existing values
 func MArray_Set[T any](array *MemArray[T], index int32, item T) {
 	array.Set(index, item)
@@ -248,6 +540,46 @@ func MArray_RemoveSwapback[T any](array *MemArray[T], index int32) T {
 	return array.RemoveSwapback(index)
 }
 
+// like MArray_RemoveSwapback, but ok is false instead of panicking when index is out of range
+func MArray_TryRemoveSwapback[T any](array *MemArray[T], index int32) (T, bool) {
+	return array.TryRemoveSwapback(index)
+}
+
+// removes the first value equal to value with swapback; false when it is not present
+func MArray_RemoveValue[T comparable](array *MemArray[T], value T) bool {
+	return array.RemoveValueFunc(func(item T) bool { return item == value })
+}
+
+// removes the first value matching match with swapback; false when none matches
+func MArray_RemoveValueFunc[T any](array *MemArray[T], match func(T) bool) bool {
+	return array.RemoveValueFunc(match)
+}
+
+// removes every value matching drop in one stable pass, index < length; returns the number removed
+func MArray_RemoveAll[T any](array *MemArray[T], drop func(T) bool) int32 {
+	return array.RemoveAll(drop)
+}
+
+// removes and returns the last value, ok is false when the array is empty
+func MArray_Pop[T any](array *MemArray[T]) (T, bool) {
+	return array.Pop()
+}
+
+// swaps two existing values, i and j < length; ok is false when either index is out of range
+func MArray_Swap[T any](array *MemArray[T], i, j int32) bool {
+	return array.Swap(i, j)
+}
+
+// reverses the populated region, index < length; the capacity tail is untouched
+func MArray_Reverse[T any](array *MemArray[T]) {
+	array.Reverse()
+}
+
+// sets the first count values to value, count is clamped to capacity; length becomes max(length, count)
+func MArray_Fill[T any](array *MemArray[T], value T, count int32) {
+	array.Fill(value, count)
+}
+
 func MArray_Reset[T any](array *MemArray[T]) {
 	array.Reset()
 }
@@ -255,14 +587,117 @@ func MArray_GetAll[T any](array *MemArray[T]) []T {
 	return array.internalArray
 }
 
+// returns a view of the populated region, len == cap == length, so appends never touch unused capacity
+func MArray_ToSlice[T any](array *MemArray[T]) []T {
+	return array.internalArray[:array.Length():array.Length()]
+}
+
+// yields the index and a pointer into the backing memory for each element in [0, length), for in-place mutation
+func MArray_Pointers[T any](array *MemArray[T]) iter.Seq2[int32, *T] {
+	return func(yield func(int32, *T) bool) {
+		for i := int32(0); i < array.Length(); i++ {
+			if !yield(i, &array.internalArray[i]) {
+				return
+			}
+		}
+	}
+}
+
+// stable sort of the populated region, index < length; the capacity tail is untouched
+func MArray_Sort[T any](array *MemArray[T], less func(a, b T) bool) {
+	view := MArray_ToSlice(array)
+	sort.SliceStable(view, func(i, j int) bool {
+		return less(view[i], view[j])
+	})
+}
+
+// binary search of the sorted populated region, index < length; cmp reports how an element
+// compares to the target (negative: element is smaller, zero: match, positive: larger).
+// Returns the match index, or the insertion index and false when there is no match.
+func MArray_BinarySearch[T any](array *MemArray[T], cmp func(T) int) (int32, bool) {
+	view := MArray_ToSlice(array)
+	index := sort.Search(len(view), func(i int) bool {
+		return cmp(view[i]) >= 0
+	})
+	return int32(index), index < len(view) && cmp(view[index]) == 0
+}
+
+// returns a new array of the same capacity holding f applied to each value, index < length
+func MArray_Map[T, U any](array *MemArray[T], f func(T) U) MemArray[U] {
+	mapped := MemArray[U]{
+		ZeroValuePtr:  new(U),
+		internalArray: make([]U, array.Length(), array.Capacity()),
+	}
+	for i, item := range MArray_ToSlice(array) {
+		mapped.internalArray[i] = f(item)
+	}
+	return mapped
+}
+
+// returns a new array of the same capacity holding, in order, the values for which keep is true
+func MArray_Filter[T any](array *MemArray[T], keep func(T) bool) MemArray[T] {
+	filtered := MemArray[T]{
+		ZeroValue:     array.ZeroValue,
+		ZeroValuePtr:  array.ZeroValuePtr,
+		internalArray: make([]T, 0, array.Capacity()),
+	}
+	for _, item := range MArray_ToSlice(array) {
+		if keep(item) {
+			filtered.internalArray = append(filtered.internalArray, item)
+		}
+	}
+	return filtered
+}
+
+// copy of the populated region, safe to hand to readers; call it under the writers' lock
+func MArray_Snapshot[T any](array *MemArray[T]) []T {
+	return array.Snapshot()
+}
+
+// copies src's populated region into dst starting at 0 and sets dst length to src length,
+// src length <= dst capacity
+func MArray_CopyFrom[T any](dst *MemArray[T], src *MemArray[T]) error {
+	return dst.CopyFrom(src)
+}
+
+// reports whether both arrays have the same length and values in [0, length); capacity is ignored
+func MArray_Equal[T comparable](a, b *MemArray[T]) bool {
+	return slices.Equal(MArray_ToSlice(a), MArray_ToSlice(b))
+}
+
+// like MArray_Equal, comparing values with eq for element types that are not comparable
+func MArray_EqualFunc[T any](a, b *MemArray[T], eq func(T, T) bool) bool {
+	return slices.EqualFunc(MArray_ToSlice(a), MArray_ToSlice(b), eq)
+}
+
 func MArray_Shrink[T any](array *MemArray[T], length int32) {
 	array.Shrink(length)
 }
 
+// keeps the first newLength elements, 0 <= newLength <= length; the dropped tail is zeroed
+func MArray_Truncate[T any](array *MemArray[T], newLength int32) bool {
+	return array.Truncate(newLength)
+}
+
 func MArray_Grow[T any](array *MemArray[T], length int32) {
 	array.Grow(length)
 }
 
+// reallocates to newCapacity, newCapacity >= length; invalidates previously obtained pointers
+func MArray_GrowCapacity[T any](array *MemArray[T], newCapacity int32) error {
+	return array.GrowCapacity(newCapacity)
+}
+
+// reallocates only if capacity < minCapacity; invalidates previously obtained pointers when it does
+func MArray_Reserve[T any](array *MemArray[T], minCapacity int32) error {
+	return array.Reserve(minCapacity)
+}
+
+// reallocates to newCapacity inside arena, newCapacity >= length; the old block is not reclaimed
+func MArray_GrowInArena[T any](array *MemArray[T], arena *Arena, newCapacity int32) error {
+	return array.GrowCapacityInArena(arena, newCapacity)
+}
+
 func MArray_IndexOf[T any](array *MemArray[T], item *T) int32 {
 	for i := int32(0); i < array.Length(); i++ {
 		//unsafe pointer compare
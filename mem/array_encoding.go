@@ -0,0 +1,83 @@
+package mem
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// memArrayEncoded is the wire form of a MemArray for JSON and gob: the populated region
+// plus the capacity needed to rebuild the backing slice.
+type memArrayEncoded[T any] struct {
+	Capacity int32 `json:"capacity"`
+	Items    []T   `json:"items"`
+}
+
+// MarshalJSON encodes only the populated region [0, Length) and the capacity;
+// the unused capacity tail is not emitted.
+func (m MemArray[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(memArrayEncoded[T]{
+		Capacity: m.Capacity(),
+		Items:    MArray_ToSlice(&m),
+	})
+}
+
+// UnmarshalJSON rebuilds the backing slice at the recorded capacity with Length set
+// to the number of decoded items.
+func (m *MemArray[T]) UnmarshalJSON(data []byte) error {
+	var decoded memArrayEncoded[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	return m.restore(decoded)
+}
+
+// GobEncode encodes the capacity and the populated region [0, Length).
+func (m MemArray[T]) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(memArrayEncoded[T]{
+		Capacity: m.Capacity(),
+		Items:    MArray_ToSlice(&m),
+	})
+	return buffer.Bytes(), err
+}
+
+// GobDecode rebuilds the backing slice at the recorded capacity with Length set
+// to the number of decoded items.
+func (m *MemArray[T]) GobDecode(data []byte) error {
+	var decoded memArrayEncoded[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	return m.restore(decoded)
+}
+
+func (m *MemArray[T]) restore(decoded memArrayEncoded[T]) error {
+	if decoded.Capacity < int32(len(decoded.Items)) {
+		return fmt.Errorf("MemArray capacity %d is less than item count %d", decoded.Capacity, len(decoded.Items))
+	}
+
+	m.internalArray = make([]T, len(decoded.Items), decoded.Capacity)
+	copy(m.internalArray, decoded.Items)
+	return nil
+}
+
+// GobEncode encodes the elements [0, Length) of the slice. Since a MemSlice is a view,
+// decoding does not restore the link to the original backing array: it produces an
+// independent MemSlice over a freshly allocated copy of the elements.
+func (s MemSlice[T]) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	err := gob.NewEncoder(&buffer).Encode(s.internalArray)
+	return buffer.Bytes(), err
+}
+
+// GobDecode materializes the encoded elements into a new backing array.
+func (s *MemSlice[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&items); err != nil {
+		return err
+	}
+	s.internalArray = items
+	return nil
+}
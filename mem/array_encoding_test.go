@@ -0,0 +1,170 @@
+package mem
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMemArray_JSON(t *testing.T) {
+	t.Run("round-trips int arrays", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Add(&arr, 3)
+
+		data, err := json.Marshal(arr)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if string(data) != `{"capacity":10,"items":[1,2,3]}` {
+			t.Errorf("unexpected JSON: %s", data)
+		}
+
+		var decoded MemArray[int]
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if decoded.Length() != 3 || decoded.Capacity() != 10 {
+			t.Errorf("expected length 3 and capacity 10, got %d and %d", decoded.Length(), decoded.Capacity())
+		}
+		for i := int32(0); i < 3; i++ {
+			if MArray_GetValue(&decoded, i) != int(i)+1 {
+				t.Errorf("index %d: expected %d, got %d", i, i+1, MArray_GetValue(&decoded, i))
+			}
+		}
+	})
+
+	t.Run("round-trips struct arrays", func(t *testing.T) {
+		type point struct {
+			X, Y int
+		}
+		arr := NewMemArray[point](8)
+		MArray_Add(&arr, point{X: 1, Y: 2})
+		MArray_Add(&arr, point{X: 3, Y: 4})
+
+		data, err := json.Marshal(&arr)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if strings.Count(string(data), `"X"`) != 2 {
+			t.Errorf("expected only the 2 populated elements in JSON, got %s", data)
+		}
+
+		var decoded MemArray[point]
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if decoded.Length() != 2 || decoded.Capacity() != 8 {
+			t.Errorf("expected length 2 and capacity 8, got %d and %d", decoded.Length(), decoded.Capacity())
+		}
+		if MArray_GetValue(&decoded, 1) != (point{X: 3, Y: 4}) {
+			t.Errorf("expected {3 4}, got %+v", MArray_GetValue(&decoded, 1))
+		}
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		data, _ := json.Marshal(arr)
+		if string(data) != `{"capacity":4,"items":[]}` {
+			t.Errorf("unexpected JSON: %s", data)
+		}
+	})
+
+	t.Run("rejects capacity below item count", func(t *testing.T) {
+		var decoded MemArray[int]
+
+		if err := json.Unmarshal([]byte(`{"capacity":1,"items":[1,2]}`), &decoded); err == nil {
+			t.Error("expected error for capacity < item count")
+		}
+	})
+}
+
+type gobPoint struct {
+	X, Y int
+}
+
+func TestMemArray_Gob(t *testing.T) {
+	t.Run("round-trips struct arrays", func(t *testing.T) {
+		arr := NewMemArray[gobPoint](8)
+		MArray_Add(&arr, gobPoint{X: 1, Y: 2})
+		MArray_Add(&arr, gobPoint{X: 3, Y: 4})
+
+		var buffer bytes.Buffer
+		if err := gob.NewEncoder(&buffer).Encode(arr); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var decoded MemArray[gobPoint]
+		if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if decoded.Length() != 2 || decoded.Capacity() != 8 {
+			t.Errorf("expected length 2 and capacity 8, got %d and %d", decoded.Length(), decoded.Capacity())
+		}
+		if MArray_GetValue(&decoded, 0) != (gobPoint{X: 1, Y: 2}) || MArray_GetValue(&decoded, 1) != (gobPoint{X: 3, Y: 4}) {
+			t.Errorf("unexpected elements %v", MArray_ToSlice(&decoded))
+		}
+	})
+
+	t.Run("round-trips interface elements of a registered type", func(t *testing.T) {
+		gob.Register(gobPoint{})
+		arr := NewMemArray[any](4)
+		MArray_Add[any](&arr, gobPoint{X: 5, Y: 6})
+
+		var buffer bytes.Buffer
+		if err := gob.NewEncoder(&buffer).Encode(arr); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var decoded MemArray[any]
+		if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if decoded.Length() != 1 || MArray_GetValue(&decoded, 0) != (gobPoint{X: 5, Y: 6}) {
+			t.Errorf("expected [{5 6}], got %v", MArray_ToSlice(&decoded))
+		}
+	})
+
+	t.Run("empty array keeps its capacity", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		var buffer bytes.Buffer
+		gob.NewEncoder(&buffer).Encode(arr)
+		var decoded MemArray[int]
+		if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if decoded.Length() != 0 || decoded.Capacity() != 4 {
+			t.Errorf("expected length 0 and capacity 4, got %d and %d", decoded.Length(), decoded.Capacity())
+		}
+	})
+}
+
+func TestMemSlice_Gob(t *testing.T) {
+	t.Run("decodes an independent copy", func(t *testing.T) {
+		backing := []int{1, 2, 3, 4}
+		slice := NewMemSliceWithData(backing[1:3])
+
+		var buffer bytes.Buffer
+		if err := gob.NewEncoder(&buffer).Encode(slice); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		var decoded MemSlice[int]
+		if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if decoded.Length() != 2 || MSlice_GetValue(&decoded, 0) != 2 || MSlice_GetValue(&decoded, 1) != 3 {
+			t.Errorf("expected [2 3], got %v", decoded.InternalArray())
+		}
+		MSlice_Set(&decoded, 0, 100)
+		if backing[1] != 2 {
+			t.Error("expected decoded slice not to share the original backing array")
+		}
+	})
+}
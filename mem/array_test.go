@@ -1,7 +1,13 @@
 package mem
 
 import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
+	"unsafe"
 )
 
 func TestNewMemArray(t *testing.T) {
@@ -31,15 +37,23 @@ func TestNewMemArray(t *testing.T) {
 
 	})
 
-	t.Run("panic when creating array with zero capacity", func(t *testing.T) {
-		func() {
-			defer func() {
-				if r := recover(); r == nil {
-					t.Error("expected panic when creating array with zero capacity")
-				}
-			}()
-			NewMemArray[int](0)
-		}()
+	t.Run("creates empty array with zero capacity", func(t *testing.T) {
+		arr := NewMemArray[int](0)
+
+		if arr.Length() != 0 || arr.Capacity() != 0 {
+			t.Errorf("expected Length = 0 and Capacity = 0, got %d and %d", arr.Length(), arr.Capacity())
+		}
+	})
+
+	t.Run("clamps negative capacity to zero", func(t *testing.T) {
+		arr := NewMemArray[int](-1)
+
+		if arr.Length() != 0 || arr.Capacity() != 0 {
+			t.Errorf("expected Length = 0 and Capacity = 0, got %d and %d", arr.Length(), arr.Capacity())
+		}
+		if _, ok := MArray_Pop(&arr); ok {
+			t.Error("expected Pop on an empty array to report !ok")
+		}
 	})
 
 	// t.Run("creates array with different types", func(t *testing.T) {
@@ -770,3 +784,1449 @@ func TestMemArray_WithArena(t *testing.T) {
 		}
 	})
 }
+
+func TestMArray_ToSlice(t *testing.T) {
+	t.Run("returns view bounded to length", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Add(&arr, 3)
+
+		view := MArray_ToSlice(&arr)
+		if len(view) != 3 {
+			t.Errorf("expected len = 3, got %d", len(view))
+		}
+		if cap(view) != 3 {
+			t.Errorf("expected cap = 3, got %d", cap(view))
+		}
+	})
+
+	t.Run("modifications reflect in array", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		view := MArray_ToSlice(&arr)
+		view[1] = 20
+
+		if MArray_GetValue(&arr, 1) != 20 {
+			t.Errorf("expected arr[1] = 20, got %d", MArray_GetValue(&arr, 1))
+		}
+	})
+
+	t.Run("append does not write into unused capacity", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		MArray_Add(&arr, 1)
+
+		view := MArray_ToSlice(&arr)
+		_ = append(view, 99)
+
+		MArray_Grow(&arr, 1)
+		if MArray_GetValue(&arr, 1) != 0 {
+			t.Errorf("expected unused capacity untouched, got %d", MArray_GetValue(&arr, 1))
+		}
+	})
+}
+
+func TestMArray_Sort(t *testing.T) {
+	newArray := func(values ...int) MemArray[int] {
+		arr := NewMemArray[int](10)
+		for _, value := range values {
+			MArray_Add(&arr, value)
+		}
+		return arr
+	}
+
+	t.Run("sorts ints ascending", func(t *testing.T) {
+		arr := newArray(5, 3, 9, 1, 7)
+		MArray_Sort(&arr, func(a, b int) bool { return a < b })
+
+		expected := []int{1, 3, 5, 7, 9}
+		for i, value := range expected {
+			if MArray_GetValue(&arr, int32(i)) != value {
+				t.Errorf("expected arr[%d] = %d, got %d", i, value, MArray_GetValue(&arr, int32(i)))
+			}
+		}
+		if arr.Length() != 5 {
+			t.Errorf("expected Length = 5, got %d", arr.Length())
+		}
+	})
+
+	t.Run("sorts ints descending", func(t *testing.T) {
+		arr := newArray(5, 3, 9, 1, 7)
+		MArray_Sort(&arr, func(a, b int) bool { return a > b })
+
+		expected := []int{9, 7, 5, 3, 1}
+		for i, value := range expected {
+			if MArray_GetValue(&arr, int32(i)) != value {
+				t.Errorf("expected arr[%d] = %d, got %d", i, value, MArray_GetValue(&arr, int32(i)))
+			}
+		}
+	})
+
+	t.Run("sorts structs by field stably", func(t *testing.T) {
+		type item struct {
+			Key   int
+			Label string
+		}
+		arr := NewMemArray[item](5)
+		MArray_Add(&arr, item{2, "a"})
+		MArray_Add(&arr, item{1, "b"})
+		MArray_Add(&arr, item{2, "c"})
+		MArray_Add(&arr, item{1, "d"})
+
+		MArray_Sort(&arr, func(a, b item) bool { return a.Key < b.Key })
+
+		expected := []string{"b", "d", "a", "c"}
+		for i, label := range expected {
+			if MArray_GetValue(&arr, int32(i)).Label != label {
+				t.Errorf("expected arr[%d].Label = %q, got %q", i, label, MArray_GetValue(&arr, int32(i)).Label)
+			}
+		}
+	})
+
+	t.Run("does not reorder the capacity tail", func(t *testing.T) {
+		arr := newArray(5, 4, 3, 2, 1)
+		MArray_Shrink(&arr, 2) // 1 and 2 remain in the tail beyond Length
+
+		MArray_Sort(&arr, func(a, b int) bool { return a < b })
+
+		if arr.Length() != 3 {
+			t.Fatalf("expected Length = 3, got %d", arr.Length())
+		}
+		MArray_Grow(&arr, 2)
+		if MArray_GetValue(&arr, 3) != 2 || MArray_GetValue(&arr, 4) != 1 {
+			t.Errorf("expected tail [2 1], got [%d %d]", MArray_GetValue(&arr, 3), MArray_GetValue(&arr, 4))
+		}
+	})
+}
+
+func TestMArray_Pop(t *testing.T) {
+	t.Run("pops to empty in reverse order", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+		MArray_Add(&arr, 10)
+		MArray_Add(&arr, 20)
+		MArray_Add(&arr, 30)
+
+		for _, expected := range []int{30, 20, 10} {
+			value, ok := MArray_Pop(&arr)
+			if !ok {
+				t.Fatal("expected ok = true")
+			}
+			if value != expected {
+				t.Errorf("expected %d, got %d", expected, value)
+			}
+		}
+		if arr.Length() != 0 {
+			t.Errorf("expected Length = 0, got %d", arr.Length())
+		}
+	})
+
+	t.Run("pop from empty returns zero value", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+
+		value, ok := MArray_Pop(&arr)
+		if ok {
+			t.Error("expected ok = false")
+		}
+		if value != 0 {
+			t.Errorf("expected zero value, got %d", value)
+		}
+	})
+
+	t.Run("length tracks pops and adds", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Pop(&arr)
+		MArray_Add(&arr, 3)
+
+		if arr.Length() != 2 {
+			t.Errorf("expected Length = 2, got %d", arr.Length())
+		}
+		if MArray_GetValue(&arr, 1) != 3 {
+			t.Errorf("expected arr[1] = 3, got %d", MArray_GetValue(&arr, 1))
+		}
+	})
+}
+
+func TestMArray_GrowCapacity(t *testing.T) {
+	t.Run("preserves data after growth", func(t *testing.T) {
+		arr := NewMemArray[int](3)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Add(&arr, 3)
+
+		if err := MArray_GrowCapacity(&arr, 6); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arr.Capacity() != 6 {
+			t.Errorf("expected Capacity = 6, got %d", arr.Capacity())
+		}
+		if arr.Length() != 3 {
+			t.Errorf("expected Length = 3, got %d", arr.Length())
+		}
+		for i := int32(0); i < 3; i++ {
+			if MArray_GetValue(&arr, i) != int(i+1) {
+				t.Errorf("expected arr[%d] = %d, got %d", i, i+1, MArray_GetValue(&arr, i))
+			}
+		}
+
+		MArray_Add(&arr, 4)
+		if arr.Length() != 4 {
+			t.Errorf("expected Length = 4 after add, got %d", arr.Length())
+		}
+	})
+
+	t.Run("returns error when new capacity is less than length", func(t *testing.T) {
+		arr := NewMemArray[int](3)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		if err := MArray_GrowCapacity(&arr, 1); err == nil {
+			t.Error("expected error when new capacity < length")
+		}
+		if arr.Capacity() != 3 {
+			t.Errorf("expected Capacity unchanged, got %d", arr.Capacity())
+		}
+	})
+
+	t.Run("previous pointers are not updated", func(t *testing.T) {
+		arr := NewMemArray[int](2)
+		MArray_Add(&arr, 1)
+		ptr := MArray_Get(&arr, 0)
+
+		MArray_GrowCapacity(&arr, 4)
+		MArray_Set(&arr, 0, 100)
+
+		// ptr points into the old backing slice
+		if *ptr != 1 {
+			t.Errorf("expected stale pointer to keep old value 1, got %d", *ptr)
+		}
+	})
+}
+
+func TestMArray_Reserve(t *testing.T) {
+	t.Run("grows when needed", func(t *testing.T) {
+		arr := NewMemArray[int](2)
+		MArray_Add(&arr, 7)
+
+		if err := MArray_Reserve(&arr, 8); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arr.Capacity() != 8 {
+			t.Errorf("expected Capacity = 8, got %d", arr.Capacity())
+		}
+		if MArray_GetValue(&arr, 0) != 7 {
+			t.Errorf("expected arr[0] = 7, got %d", MArray_GetValue(&arr, 0))
+		}
+	})
+
+	t.Run("does not reallocate when capacity suffices", func(t *testing.T) {
+		arr := NewMemArray[int](8)
+		MArray_Add(&arr, 7)
+		ptr := MArray_Get(&arr, 0)
+
+		if err := MArray_Reserve(&arr, 4); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arr.Capacity() != 8 {
+			t.Errorf("expected Capacity = 8, got %d", arr.Capacity())
+		}
+		if MArray_Get(&arr, 0) != ptr {
+			t.Error("expected backing slice to be unchanged")
+		}
+	})
+}
+
+func TestMArray_GrowInArena(t *testing.T) {
+	t.Run("grown array lives in arena memory", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arr := NewMemArray[int64](2)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		if err := MArray_GrowInArena(&arr, arena, 8); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if arr.Capacity() != 8 {
+			t.Errorf("expected Capacity = 8, got %d", arr.Capacity())
+		}
+
+		address := uintptr(unsafe.Pointer(&arr.InternalArray()[0]))
+		if address < arena.Memory || address >= arena.Memory+arena.Capacity {
+			t.Errorf("expected backing slice inside arena [%d, %d), got %d", arena.Memory, arena.Memory+arena.Capacity, address)
+		}
+		if MArray_GetValue(&arr, 0) != 1 || MArray_GetValue(&arr, 1) != 2 {
+			t.Errorf("expected data to be preserved, got %v", arr.InternalArray())
+		}
+
+		for i := int64(3); i <= 8; i++ {
+			MArray_Add(&arr, i)
+		}
+		if arr.Length() != 8 {
+			t.Errorf("expected Length = 8, got %d", arr.Length())
+		}
+	})
+
+	t.Run("each growth consumes new arena space", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arr := NewMemArray[int64](1)
+
+		MArray_GrowInArena(&arr, arena, 4)
+		used := arena.NextAllocation
+		MArray_GrowInArena(&arr, arena, 8)
+
+		if arena.NextAllocation <= used {
+			t.Errorf("expected NextAllocation to advance past %d, got %d", used, arena.NextAllocation)
+		}
+	})
+
+	t.Run("returns error when arena is exhausted", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		arr := NewMemArray[int64](1)
+		MArray_Add(&arr, 42)
+
+		if err := MArray_GrowInArena(&arr, arena, 100); err == nil {
+			t.Error("expected error when arena capacity exceeded")
+		}
+		if arr.Capacity() != 1 || MArray_GetValue(&arr, 0) != 42 {
+			t.Error("expected array to be unchanged after failed growth")
+		}
+	})
+
+	t.Run("zeroes stale arena bytes beyond the length", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		for i := range arena.bytes() {
+			arena.bytes()[i] = 0xAB
+		}
+
+		arr := NewMemArray[int64](1)
+		MArray_Add(&arr, 7)
+		MArray_GrowInArena(&arr, arena, 8)
+		MArray_Grow(&arr, 7)
+
+		if !slices.Equal(arr.InternalArray(), []int64{7, 0, 0, 0, 0, 0, 0, 0}) {
+			t.Errorf("expected zeroed tail, got %v", arr.InternalArray())
+		}
+	})
+
+	t.Run("aligns for T with a small default alignment", func(t *testing.T) {
+		arena, _ := NewArena(alignedMemory(1024, 64), ArenaWithDefaultAlignment(1))
+		arena.Allocate(1)
+		used := arena.NextAllocation
+
+		arr := NewMemArray[int64](1)
+		MArray_Add(&arr, 1)
+		if err := MArray_GrowInArena(&arr, arena, 4); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if address := uintptr(unsafe.Pointer(&arr.InternalArray()[0])); address%unsafe.Alignof(int64(0)) != 0 {
+			t.Errorf("expected address aligned to %d, got %d", unsafe.Alignof(int64(0)), address)
+		}
+		if arena.NextAllocation <= used {
+			t.Error("expected the grown block to come from the arena")
+		}
+	})
+
+	t.Run("returns error when new capacity is less than length", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		arr := NewMemArray[int64](2)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		if err := MArray_GrowInArena(&arr, arena, 1); err == nil {
+			t.Error("expected error when new capacity < length")
+		}
+	})
+}
+
+func TestMArray_Swap(t *testing.T) {
+	t.Run("swaps valid indices", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Add(&arr, 3)
+
+		if !MArray_Swap(&arr, 0, 2) {
+			t.Fatal("expected swap to succeed")
+		}
+		if MArray_GetValue(&arr, 0) != 3 || MArray_GetValue(&arr, 2) != 1 {
+			t.Errorf("expected [3 2 1], got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("swapping an index with itself is a no-op", func(t *testing.T) {
+		arr := NewMemArray[int](2)
+		MArray_Add(&arr, 1)
+
+		if !MArray_Swap(&arr, 0, 0) || MArray_GetValue(&arr, 0) != 1 {
+			t.Error("expected self-swap to succeed without changes")
+		}
+	})
+
+	t.Run("rejects indices outside the populated region", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		for _, indices := range [][2]int32{{0, 2}, {2, 0}, {-1, 1}, {1, 3}} {
+			if MArray_Swap(&arr, indices[0], indices[1]) {
+				t.Errorf("expected swap(%d, %d) to fail", indices[0], indices[1])
+			}
+		}
+		if MArray_GetValue(&arr, 0) != 1 || MArray_GetValue(&arr, 1) != 2 {
+			t.Errorf("expected array unchanged, got %v", MArray_ToSlice(&arr))
+		}
+	})
+}
+
+func TestMArray_Reverse(t *testing.T) {
+	reversed := func(t *testing.T, values []int) {
+		t.Helper()
+		arr := NewMemArray[int](int32(len(values)) + 2)
+		for _, v := range values {
+			MArray_Add(&arr, v)
+		}
+		arr.internalArray[:arr.Capacity()][len(values)] = -1
+
+		MArray_Reverse(&arr)
+
+		for i := range values {
+			if got := MArray_GetValue(&arr, int32(i)); got != values[len(values)-1-i] {
+				t.Errorf("index %d: expected %d, got %d", i, values[len(values)-1-i], got)
+			}
+		}
+		if arr.Length() != int32(len(values)) {
+			t.Errorf("expected length %d, got %d", len(values), arr.Length())
+		}
+		if arr.internalArray[:arr.Capacity()][len(values)] != -1 {
+			t.Error("expected capacity tail to be untouched")
+		}
+	}
+
+	t.Run("reverses odd-length region", func(t *testing.T) {
+		reversed(t, []int{1, 2, 3, 4, 5})
+	})
+
+	t.Run("reverses even-length region", func(t *testing.T) {
+		reversed(t, []int{1, 2, 3, 4})
+	})
+
+	t.Run("empty array is a no-op", func(t *testing.T) {
+		arr := NewMemArray[int](2)
+
+		MArray_Reverse(&arr)
+
+		if arr.Length() != 0 {
+			t.Errorf("expected length 0, got %d", arr.Length())
+		}
+	})
+}
+
+func TestMArray_Fill(t *testing.T) {
+	t.Run("fills a fresh array and advances length", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+
+		MArray_Fill(&arr, 7, 4)
+
+		if arr.Length() != 4 {
+			t.Errorf("expected length 4, got %d", arr.Length())
+		}
+		for i := int32(0); i < 4; i++ {
+			if MArray_GetValue(&arr, i) != 7 {
+				t.Errorf("index %d: expected 7, got %d", i, MArray_GetValue(&arr, i))
+			}
+		}
+	})
+
+	t.Run("keeps length when filling fewer than length", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		for i := 1; i <= 5; i++ {
+			MArray_Add(&arr, i)
+		}
+
+		MArray_Fill(&arr, 0, 2)
+
+		if arr.Length() != 5 {
+			t.Errorf("expected length 5, got %d", arr.Length())
+		}
+		expected := []int{0, 0, 3, 4, 5}
+		for i, v := range expected {
+			if got := MArray_GetValue(&arr, int32(i)); got != v {
+				t.Errorf("index %d: expected %d, got %d", i, v, got)
+			}
+		}
+	})
+
+	t.Run("clamps count to capacity", func(t *testing.T) {
+		arr := NewMemArray[int](3)
+
+		MArray_Fill(&arr, 9, 100)
+
+		if arr.Length() != 3 {
+			t.Errorf("expected length clamped to 3, got %d", arr.Length())
+		}
+		for i := int32(0); i < 3; i++ {
+			if MArray_GetValue(&arr, i) != 9 {
+				t.Errorf("index %d: expected 9, got %d", i, MArray_GetValue(&arr, i))
+			}
+		}
+	})
+
+	t.Run("negative count is a no-op", func(t *testing.T) {
+		arr := NewMemArray[int](3)
+		MArray_Add(&arr, 1)
+
+		MArray_Fill(&arr, 9, -1)
+
+		if arr.Length() != 1 || MArray_GetValue(&arr, 0) != 1 {
+			t.Error("expected array to be unchanged")
+		}
+	})
+}
+
+func TestMArray_BinarySearch(t *testing.T) {
+	target := func(value int) func(int) int {
+		return func(element int) int {
+			return element - value
+		}
+	}
+
+	newSorted := func() MemArray[int] {
+		arr := NewMemArray[int](10)
+		for _, v := range []int{9, 1, 7, 3, 5} {
+			MArray_Add(&arr, v)
+		}
+		MArray_Sort(&arr, func(a, b int) bool { return a < b })
+		return arr
+	}
+
+	t.Run("finds present targets", func(t *testing.T) {
+		arr := newSorted()
+
+		for i, v := range []int{1, 3, 5, 7, 9} {
+			index, found := MArray_BinarySearch(&arr, target(v))
+			if !found || index != int32(i) {
+				t.Errorf("target %d: expected (%d, true), got (%d, %v)", v, i, index, found)
+			}
+		}
+	})
+
+	t.Run("returns insertion index for absent targets", func(t *testing.T) {
+		arr := newSorted()
+
+		cases := map[int]int32{0: 0, 4: 2, 8: 4, 10: 5}
+		for v, expected := range cases {
+			index, found := MArray_BinarySearch(&arr, target(v))
+			if found || index != expected {
+				t.Errorf("target %d: expected (%d, false), got (%d, %v)", v, expected, index, found)
+			}
+		}
+	})
+
+	t.Run("ignores the capacity tail", func(t *testing.T) {
+		arr := newSorted()
+		arr.internalArray[:arr.Capacity()][arr.Length()] = 100
+
+		if _, found := MArray_BinarySearch(&arr, target(100)); found {
+			t.Error("expected value in capacity tail not to be found")
+		}
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		index, found := MArray_BinarySearch(&arr, target(1))
+		if found || index != 0 {
+			t.Errorf("expected (0, false), got (%d, %v)", index, found)
+		}
+	})
+}
+
+func TestMArray_TryRemoveSwapback(t *testing.T) {
+	t.Run("removes a zero-valued element with ok", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 0)
+		MArray_Add(&arr, 5)
+
+		removed, ok := MArray_TryRemoveSwapback(&arr, 0)
+		if !ok {
+			t.Fatal("expected ok for valid index")
+		}
+		if removed != 0 {
+			t.Errorf("expected removed = 0, got %d", removed)
+		}
+		if arr.Length() != 1 || MArray_GetValue(&arr, 0) != 5 {
+			t.Errorf("expected [5], got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("out of range index is not ok", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+
+		for _, index := range []int32{-1, 1, 3} {
+			if _, ok := MArray_TryRemoveSwapback(&arr, index); ok {
+				t.Errorf("expected index %d not to be ok", index)
+			}
+		}
+		if arr.Length() != 1 {
+			t.Errorf("expected length unchanged at 1, got %d", arr.Length())
+		}
+	})
+}
+
+func TestMArray_Map(t *testing.T) {
+	t.Run("maps ints to strings", func(t *testing.T) {
+		arr := NewMemArray[int](8)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 20)
+		MArray_Add(&arr, 300)
+
+		mapped := MArray_Map(&arr, strconv.Itoa)
+
+		if mapped.Length() != 3 || mapped.Capacity() != 8 {
+			t.Fatalf("expected length 3 and capacity 8, got %d and %d", mapped.Length(), mapped.Capacity())
+		}
+		for i, expected := range []string{"1", "20", "300"} {
+			if got := MArray_GetValue(&mapped, int32(i)); got != expected {
+				t.Errorf("index %d: expected %q, got %q", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("mapping an empty array", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		mapped := MArray_Map(&arr, strconv.Itoa)
+
+		if mapped.Length() != 0 || mapped.Capacity() != 4 {
+			t.Errorf("expected length 0 and capacity 4, got %d and %d", mapped.Length(), mapped.Capacity())
+		}
+	})
+}
+
+func TestMArray_Filter(t *testing.T) {
+	t.Run("keeps even values in order", func(t *testing.T) {
+		arr := NewMemArray[int](8)
+		for i := 1; i <= 6; i++ {
+			MArray_Add(&arr, i)
+		}
+
+		evens := MArray_Filter(&arr, func(v int) bool { return v%2 == 0 })
+
+		if evens.Length() != 3 || evens.Capacity() != 8 {
+			t.Fatalf("expected length 3 and capacity 8, got %d and %d", evens.Length(), evens.Capacity())
+		}
+		for i, expected := range []int{2, 4, 6} {
+			if got := MArray_GetValue(&evens, int32(i)); got != expected {
+				t.Errorf("index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+		if arr.Length() != 6 {
+			t.Errorf("expected source length unchanged at 6, got %d", arr.Length())
+		}
+	})
+
+	t.Run("filtering everything out", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+
+		none := MArray_Filter(&arr, func(int) bool { return false })
+
+		if none.Length() != 0 {
+			t.Errorf("expected length 0, got %d", none.Length())
+		}
+	})
+}
+
+func TestMArray_CopyFrom(t *testing.T) {
+	newSource := func() MemArray[int] {
+		src := NewMemArray[int](3)
+		MArray_Add(&src, 1)
+		MArray_Add(&src, 2)
+		MArray_Add(&src, 3)
+		return src
+	}
+
+	t.Run("copies into a larger destination", func(t *testing.T) {
+		src := newSource()
+		dst := NewMemArray[int](10)
+		for i := 0; i < 5; i++ {
+			MArray_Add(&dst, 99)
+		}
+
+		if err := MArray_CopyFrom(&dst, &src); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if dst.Length() != 3 || dst.Capacity() != 10 {
+			t.Errorf("expected length 3 and capacity 10, got %d and %d", dst.Length(), dst.Capacity())
+		}
+		for i := int32(0); i < 3; i++ {
+			if MArray_GetValue(&dst, i) != MArray_GetValue(&src, i) {
+				t.Errorf("index %d: expected %d, got %d", i, MArray_GetValue(&src, i), MArray_GetValue(&dst, i))
+			}
+		}
+	})
+
+	t.Run("copies into an equal-sized destination", func(t *testing.T) {
+		src := newSource()
+		dst := NewMemArray[int](3)
+
+		if err := MArray_CopyFrom(&dst, &src); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if dst.Length() != 3 || MArray_GetValue(&dst, 2) != 3 {
+			t.Errorf("expected [1 2 3], got %v", MArray_ToSlice(&dst))
+		}
+		MArray_Set(&dst, 0, 100)
+		if MArray_GetValue(&src, 0) != 1 {
+			t.Error("expected source to be unaffected by writes to the copy")
+		}
+	})
+
+	t.Run("returns error when destination is too small", func(t *testing.T) {
+		src := newSource()
+		dst := NewMemArray[int](2)
+		MArray_Add(&dst, 7)
+
+		if err := MArray_CopyFrom(&dst, &src); err == nil {
+			t.Error("expected error when destination capacity < source length")
+		}
+		if dst.Length() != 1 || MArray_GetValue(&dst, 0) != 7 {
+			t.Error("expected destination to be unchanged")
+		}
+	})
+}
+
+func TestMArray_RemoveAll(t *testing.T) {
+	t.Run("removes every other element preserving order", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		for i := 0; i < 8; i++ {
+			MArray_Add(&arr, i)
+		}
+
+		removed := MArray_RemoveAll(&arr, func(v int) bool { return v%2 == 1 })
+
+		if removed != 4 {
+			t.Errorf("expected 4 removed, got %d", removed)
+		}
+		if arr.Length() != 4 {
+			t.Fatalf("expected length 4, got %d", arr.Length())
+		}
+		for i, expected := range []int{0, 2, 4, 6} {
+			if got := MArray_GetValue(&arr, int32(i)); got != expected {
+				t.Errorf("index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("removing none leaves the array unchanged", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+		MArray_Add(&arr, 3)
+
+		removed := MArray_RemoveAll(&arr, func(int) bool { return false })
+
+		if removed != 0 || arr.Length() != 3 {
+			t.Errorf("expected 0 removed and length 3, got %d and %d", removed, arr.Length())
+		}
+		for i, expected := range []int{1, 2, 3} {
+			if got := MArray_GetValue(&arr, int32(i)); got != expected {
+				t.Errorf("index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("removing all empties the array", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		removed := MArray_RemoveAll(&arr, func(int) bool { return true })
+
+		if removed != 2 || arr.Length() != 0 || arr.Capacity() != 4 {
+			t.Errorf("expected 2 removed, length 0, capacity 4; got %d, %d, %d", removed, arr.Length(), arr.Capacity())
+		}
+	})
+}
+
+func TestMemArray_CapacityInvariant(t *testing.T) {
+	t.Run("Add on a literal array guards against the backing capacity", func(t *testing.T) {
+		arr := MemArray[int]{internalArray: make([]int, 0, 2)}
+
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		if arr.Length() != 2 || arr.Capacity() != 2 {
+			t.Fatalf("expected Length = 2 and Capacity = 2, got %d and %d", arr.Length(), arr.Capacity())
+		}
+		defer func() {
+			r := recover()
+			message, ok := r.(string)
+			if !ok {
+				t.Fatalf("expected a capacity exceeded panic, got %v", r)
+			}
+			if message != "MemArray.Add capacity exceeded: 2 + 1 > 2" {
+				t.Errorf("unexpected panic message %q", message)
+			}
+		}()
+		MArray_Add(&arr, 3)
+	})
+
+	t.Run("zero value array reports zero capacity", func(t *testing.T) {
+		var arr MemArray[int]
+
+		if arr.Length() != 0 || arr.Capacity() != 0 {
+			t.Errorf("expected Length = 0 and Capacity = 0, got %d and %d", arr.Length(), arr.Capacity())
+		}
+	})
+}
+
+func TestMArray_Equal(t *testing.T) {
+	newArray := func(capacity int32, values ...int) MemArray[int] {
+		arr := NewMemArray[int](capacity)
+		for _, v := range values {
+			MArray_Add(&arr, v)
+		}
+		return arr
+	}
+
+	t.Run("equal contents with different capacities", func(t *testing.T) {
+		a := newArray(3, 1, 2, 3)
+		b := newArray(10, 1, 2, 3)
+
+		if !MArray_Equal(&a, &b) {
+			t.Error("expected arrays to be equal")
+		}
+	})
+
+	t.Run("ignores the unused tail", func(t *testing.T) {
+		a := newArray(4, 1, 2, 3)
+		b := newArray(4, 1, 2)
+		MArray_Pop(&a)
+
+		if !MArray_Equal(&a, &b) {
+			t.Error("expected stale values beyond Length to be ignored")
+		}
+	})
+
+	t.Run("unequal lengths", func(t *testing.T) {
+		a := newArray(4, 1, 2)
+		b := newArray(4, 1, 2, 3)
+
+		if MArray_Equal(&a, &b) {
+			t.Error("expected arrays of different lengths to differ")
+		}
+	})
+
+	t.Run("element mismatch", func(t *testing.T) {
+		a := newArray(4, 1, 2, 3)
+		b := newArray(4, 1, 5, 3)
+
+		if MArray_Equal(&a, &b) {
+			t.Error("expected arrays with different elements to differ")
+		}
+	})
+
+	t.Run("empty arrays are equal", func(t *testing.T) {
+		a := newArray(0)
+		b := newArray(5)
+
+		if !MArray_Equal(&a, &b) {
+			t.Error("expected empty arrays to be equal")
+		}
+	})
+}
+
+func TestMArray_EqualFunc(t *testing.T) {
+	sameValues := func(a, b []int) bool { return slices.Equal(a, b) }
+
+	t.Run("compares non-comparable elements with the predicate", func(t *testing.T) {
+		a := NewMemArray[[]int](2)
+		MArray_Add(&a, []int{1, 2})
+		b := NewMemArray[[]int](5)
+		MArray_Add(&b, []int{1, 2})
+
+		if !MArray_EqualFunc(&a, &b, sameValues) {
+			t.Error("expected arrays to be equal")
+		}
+
+		MArray_Set(&b, 0, []int{1, 3})
+		if MArray_EqualFunc(&a, &b, sameValues) {
+			t.Error("expected arrays with different elements to differ")
+		}
+	})
+
+	t.Run("unequal lengths", func(t *testing.T) {
+		a := NewMemArray[[]int](2)
+		MArray_Add(&a, []int{1})
+		b := NewMemArray[[]int](2)
+
+		if MArray_EqualFunc(&a, &b, sameValues) {
+			t.Error("expected arrays of different lengths to differ")
+		}
+	})
+}
+
+func TestMArray_Pointers(t *testing.T) {
+	type counter struct {
+		id    int32
+		value int
+	}
+
+	t.Run("mutates elements in place", func(t *testing.T) {
+		arr := NewMemArray[counter](5)
+		for i := int32(0); i < 3; i++ {
+			MArray_Add(&arr, counter{id: i, value: int(i) * 10})
+		}
+
+		for i, p := range MArray_Pointers(&arr) {
+			if p.id != i {
+				t.Errorf("expected index %d to yield element %d", i, p.id)
+			}
+			p.value++
+		}
+
+		for i, expected := range []int{1, 11, 21} {
+			if got := MArray_Get(&arr, int32(i)).value; got != expected {
+				t.Errorf("expected value %d at index %d, got %d", expected, i, got)
+			}
+		}
+	})
+
+	t.Run("skips unpopulated capacity", func(t *testing.T) {
+		arr := NewMemArray[counter](5)
+		MArray_Add(&arr, counter{})
+
+		count := 0
+		for range MArray_Pointers(&arr) {
+			count++
+		}
+		if count != 1 {
+			t.Errorf("expected 1 iteration, got %d", count)
+		}
+	})
+
+	t.Run("stops early on break", func(t *testing.T) {
+		arr := NewMemArray[counter](5)
+		for i := 0; i < 5; i++ {
+			MArray_Add(&arr, counter{})
+		}
+
+		count := 0
+		for _, p := range MArray_Pointers(&arr) {
+			p.value = 1
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if MArray_Get(&arr, 2).value != 0 {
+			t.Error("expected elements after the break to be untouched")
+		}
+	})
+}
+
+func TestMArray_Snapshot(t *testing.T) {
+	t.Run("returns an independent copy of the populated region", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+		MArray_Add(&arr, 1)
+		MArray_Add(&arr, 2)
+
+		snapshot := MArray_Snapshot(&arr)
+		MArray_Set(&arr, 0, 10)
+		MArray_Add(&arr, 3)
+
+		if !slices.Equal(snapshot, []int{1, 2}) {
+			t.Errorf("expected snapshot [1 2], got %v", snapshot)
+		}
+	})
+
+	t.Run("empty array gives an empty snapshot", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+
+		if snapshot := MArray_Snapshot(&arr); len(snapshot) != 0 {
+			t.Errorf("expected empty snapshot, got %v", snapshot)
+		}
+	})
+
+	t.Run("readers snapshot while a writer appends", func(t *testing.T) {
+		const count = 1000
+		arr := NewMemArray[int](count)
+		var mu sync.RWMutex
+		var wg sync.WaitGroup
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				mu.Lock()
+				MArray_Add(&arr, i)
+				mu.Unlock()
+			}
+		}()
+
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for done := false; !done; {
+					mu.RLock()
+					snapshot := MArray_Snapshot(&arr)
+					mu.RUnlock()
+
+					for i, value := range snapshot {
+						if value != i {
+							t.Errorf("inconsistent snapshot: index %d holds %d", i, value)
+							return
+						}
+					}
+					done = len(snapshot) == count
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestMArray_Truncate(t *testing.T) {
+	newArray := func() MemArray[int] {
+		arr := NewMemArray[int](10)
+		for i := 1; i <= 5; i++ {
+			MArray_Add(&arr, i)
+		}
+		return arr
+	}
+
+	t.Run("truncates to a smaller length", func(t *testing.T) {
+		arr := newArray()
+
+		if !MArray_Truncate(&arr, 3) {
+			t.Fatal("expected truncate to apply")
+		}
+		if !slices.Equal(MArray_ToSlice(&arr), []int{1, 2, 3}) {
+			t.Errorf("expected [1 2 3], got %v", MArray_ToSlice(&arr))
+		}
+		if arr.Capacity() != 10 {
+			t.Errorf("expected capacity 10, got %d", arr.Capacity())
+		}
+	})
+
+	t.Run("truncates to zero", func(t *testing.T) {
+		arr := newArray()
+
+		if !MArray_Truncate(&arr, 0) || arr.Length() != 0 {
+			t.Errorf("expected empty array, got length %d", arr.Length())
+		}
+	})
+
+	t.Run("zeroes the dropped tail", func(t *testing.T) {
+		arr := newArray()
+		MArray_Truncate(&arr, 2)
+		MArray_Grow(&arr, 3)
+
+		if !slices.Equal(MArray_ToSlice(&arr), []int{1, 2, 0, 0, 0}) {
+			t.Errorf("expected dropped elements to be zeroed, got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("rejects lengths outside [0, Length]", func(t *testing.T) {
+		arr := newArray()
+
+		for _, length := range []int32{6, 10, -1} {
+			if MArray_Truncate(&arr, length) {
+				t.Errorf("expected truncate to %d to be rejected", length)
+			}
+		}
+		if arr.Length() != 5 {
+			t.Errorf("expected length unchanged, got %d", arr.Length())
+		}
+	})
+}
+
+func TestMArray_AddGrowing(t *testing.T) {
+	t.Run("grows past the initial capacity and keeps all elements", func(t *testing.T) {
+		arr := NewMemArray[int](2)
+		for i := 0; i < 10; i++ {
+			MArray_AddGrowing(&arr, i)
+		}
+
+		if arr.Length() != 10 {
+			t.Errorf("expected length 10, got %d", arr.Length())
+		}
+		if arr.Capacity() != 16 {
+			t.Errorf("expected capacity to double to 16, got %d", arr.Capacity())
+		}
+		for i := int32(0); i < 10; i++ {
+			if MArray_GetValue(&arr, i) != int(i) {
+				t.Errorf("expected %d at index %d, got %d", i, i, MArray_GetValue(&arr, i))
+			}
+		}
+	})
+
+	t.Run("grows a zero-capacity array", func(t *testing.T) {
+		arr := NewMemArray[int](0)
+		MArray_AddGrowing(&arr, 7)
+
+		if arr.Length() != 1 || MArray_GetValue(&arr, 0) != 7 {
+			t.Errorf("expected [7], got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("does not reallocate while there is room", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		first, _ := MArray_AddGrowing(&arr, 1)
+		MArray_AddGrowing(&arr, 2)
+
+		if first != MArray_Get(&arr, 0) {
+			t.Error("expected the backing slice to be kept")
+		}
+	})
+
+	t.Run("new capacity is computed without int32 overflow", func(t *testing.T) {
+		if capacity, err := grownCapacity(math.MaxInt32-1, math.MaxInt32); err != nil || capacity != math.MaxInt32 {
+			t.Errorf("expected capacity clamped to MaxInt32, got %d, %v", capacity, err)
+		}
+		if capacity, err := grownCapacity(1<<30, 1<<30+1); err != nil || capacity != math.MaxInt32 {
+			t.Errorf("expected doubling past MaxInt32 to clamp, got %d, %v", capacity, err)
+		}
+		if _, err := grownCapacity(math.MaxInt32, math.MaxInt32+1); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded past MaxInt32, got %v", err)
+		}
+	})
+
+	t.Run("MemArrayWithGrowable makes Add grow", func(t *testing.T) {
+		arr := NewMemArray[int](1, MemArrayWithGrowable[int]())
+		for i := 0; i < 5; i++ {
+			MArray_Add(&arr, i)
+		}
+
+		if !slices.Equal(MArray_ToSlice(&arr), []int{0, 1, 2, 3, 4}) {
+			t.Errorf("expected [0 1 2 3 4], got %v", MArray_ToSlice(&arr))
+		}
+		if arr.Capacity() != 8 {
+			t.Errorf("expected capacity 8, got %d", arr.Capacity())
+		}
+	})
+
+	t.Run("Add still panics on a full array by default", func(t *testing.T) {
+		arr := NewMemArray[int](1)
+		MArray_Add(&arr, 1)
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic when adding to a full array")
+			}
+		}()
+		MArray_Add(&arr, 2)
+	})
+}
+
+func TestMArray_GetOr(t *testing.T) {
+	arr := NewMemArray[int](5)
+	MArray_Add(&arr, 10)
+	MArray_Add(&arr, 20)
+
+	t.Run("returns the element when in range", func(t *testing.T) {
+		if value := MArray_GetOr(&arr, 1, -1); value != 20 {
+			t.Errorf("expected 20, got %d", value)
+		}
+	})
+
+	t.Run("returns the default when out of range", func(t *testing.T) {
+		for _, index := range []int32{-1, 2, 4, 5, 100} {
+			if value := MArray_GetOr(&arr, index, -1); value != -1 {
+				t.Errorf("index %d: expected default -1, got %d", index, value)
+			}
+		}
+	})
+}
+
+func TestNewMemArrayFromSlice(t *testing.T) {
+	t.Run("round-trips through MArray_ToSlice", func(t *testing.T) {
+		src := []string{"a", "b", "c"}
+
+		arr := NewMemArrayFromSlice(src)
+
+		if arr.Length() != 3 || arr.Capacity() != 3 {
+			t.Errorf("expected length and capacity 3, got %d and %d", arr.Length(), arr.Capacity())
+		}
+		if !slices.Equal(MArray_ToSlice(&arr), src) {
+			t.Errorf("expected %v, got %v", src, MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("is independent of the source slice", func(t *testing.T) {
+		src := []int{1, 2, 3}
+		arr := NewMemArrayFromSlice(src)
+
+		src[0] = 100
+		if MArray_GetValue(&arr, 0) != 1 {
+			t.Errorf("expected array to keep 1, got %d", MArray_GetValue(&arr, 0))
+		}
+
+		MArray_Set(&arr, 1, 200)
+		if src[1] != 2 {
+			t.Errorf("expected source to keep 2, got %d", src[1])
+		}
+	})
+
+	t.Run("empty and nil slices give an empty array", func(t *testing.T) {
+		for _, src := range [][]int{nil, {}} {
+			arr := NewMemArrayFromSlice(src)
+			if arr.Length() != 0 || arr.Capacity() != 0 {
+				t.Errorf("expected empty array, got length %d and capacity %d", arr.Length(), arr.Capacity())
+			}
+		}
+	})
+}
+
+func TestMArray_RemoveValue(t *testing.T) {
+	newArray := func(values ...int) MemArray[int] {
+		arr := NewMemArray[int](10)
+		for _, value := range values {
+			MArray_Add(&arr, value)
+		}
+		return arr
+	}
+
+	t.Run("removes a present value with swapback", func(t *testing.T) {
+		arr := newArray(1, 2, 3, 4)
+
+		if !MArray_RemoveValue(&arr, 2) {
+			t.Fatal("expected value to be found")
+		}
+		if !slices.Equal(MArray_ToSlice(&arr), []int{1, 4, 3}) {
+			t.Errorf("expected [1 4 3], got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("absent value leaves the array unchanged", func(t *testing.T) {
+		arr := newArray(1, 2, 3)
+
+		if MArray_RemoveValue(&arr, 5) {
+			t.Error("expected value not to be found")
+		}
+		if arr.Length() != 3 {
+			t.Errorf("expected length 3, got %d", arr.Length())
+		}
+	})
+
+	t.Run("removes only the first of several equal values", func(t *testing.T) {
+		arr := newArray(7, 1, 7, 7)
+
+		MArray_RemoveValue(&arr, 7)
+
+		count := 0
+		for _, value := range MArray_ToSlice(&arr) {
+			if value == 7 {
+				count++
+			}
+		}
+		if arr.Length() != 3 || count != 2 {
+			t.Errorf("expected two 7s left in 3 elements, got %v", MArray_ToSlice(&arr))
+		}
+	})
+
+	t.Run("RemoveValueFunc matches non-comparable values", func(t *testing.T) {
+		arr := NewMemArray[[]int](4)
+		MArray_Add(&arr, []int{1})
+		MArray_Add(&arr, []int{2, 3})
+
+		removed := MArray_RemoveValueFunc(&arr, func(item []int) bool { return slices.Equal(item, []int{2, 3}) })
+
+		if !removed || arr.Length() != 1 {
+			t.Errorf("expected one element left, got %v (removed=%v)", MArray_ToSlice(&arr), removed)
+		}
+		if MArray_RemoveValueFunc(&arr, func(item []int) bool { return len(item) == 5 }) {
+			t.Error("expected no match")
+		}
+	})
+}
+
+func TestMArray_GetWrapped(t *testing.T) {
+	arr := NewMemArray[int](5)
+	for _, value := range []int{10, 20, 30} {
+		MArray_Add(&arr, value)
+	}
+
+	t.Run("indices within Length", func(t *testing.T) {
+		if p := MArray_GetWrapped(&arr, 1); p != MArray_Get(&arr, 1) {
+			t.Error("expected a pointer to the element at index 1")
+		}
+	})
+
+	t.Run("indices beyond Length wrap around", func(t *testing.T) {
+		for index, expected := range map[int32]int{3: 10, 4: 20, 8: 30, 300: 10} {
+			if got := *MArray_GetWrapped(&arr, index); got != expected {
+				t.Errorf("index %d: expected %d, got %d", index, expected, got)
+			}
+		}
+	})
+
+	t.Run("negative indices count from the end", func(t *testing.T) {
+		for index, expected := range map[int32]int{-1: 30, -3: 10, -4: 30, -301: 30} {
+			if got := *MArray_GetWrapped(&arr, index); got != expected {
+				t.Errorf("index %d: expected %d, got %d", index, expected, got)
+			}
+		}
+	})
+
+	t.Run("empty array returns nil", func(t *testing.T) {
+		empty := NewMemArray[int](5)
+		if p := MArray_GetWrapped(&empty, 0); p != nil {
+			t.Errorf("expected nil, got %v", *p)
+		}
+	})
+}
+
+func TestMArray_ReinterpretAs(t *testing.T) {
+	type pair struct {
+		a int64
+		b int64
+	}
+	newBytes := func(length int32) MemArray[byte] {
+		arena := NewArenaWithSizeUnsafe(256)
+		arr := NewMemArray[byte](0)
+		MArray_GrowInArena(&arr, arena, 64)
+		MArray_Grow(&arr, length)
+		return arr
+	}
+
+	t.Run("views 16 bytes as one two-int64 struct", func(t *testing.T) {
+		src := newBytes(16)
+
+		pairs, err := MArray_ReinterpretAs[pair](&src)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if pairs.Length() != 1 || pairs.Capacity() != 1 {
+			t.Errorf("expected length and capacity 1, got %d and %d", pairs.Length(), pairs.Capacity())
+		}
+		p := MArray_Get(&pairs, 0)
+		if unsafe.Pointer(p) != unsafe.Pointer(MArray_Get(&src, 0)) {
+			t.Fatal("expected the view to alias the original bytes")
+		}
+
+		p.a, p.b = -1, 0
+		for i := int32(0); i < 16; i++ {
+			expected := byte(0)
+			if i < 8 {
+				expected = 0xff
+			}
+			if MArray_GetValue(&src, i) != expected {
+				t.Errorf("byte %d: expected %#x, got %#x", i, expected, MArray_GetValue(&src, i))
+			}
+		}
+
+		MArray_Set(&src, 8, 1)
+		if p.b == 0 {
+			t.Error("expected writes to the bytes to show in the struct")
+		}
+	})
+
+	t.Run("rejects a length that is not a multiple of the element size", func(t *testing.T) {
+		src := newBytes(12)
+
+		if _, err := MArray_ReinterpretAs[pair](&src); err == nil {
+			t.Error("expected error for 12 bytes")
+		}
+	})
+
+	t.Run("rejects misaligned bytes", func(t *testing.T) {
+		src := newBytes(20)
+		misaligned := NewMemArray[byte](0)
+		misaligned.internalArray = src.internalArray[1:17:17]
+
+		if _, err := MArray_ReinterpretAs[pair](&misaligned); err == nil {
+			t.Error("expected error for misaligned bytes")
+		}
+	})
+}
+
+func TestMArray_AppendCopy(t *testing.T) {
+	t.Run("appends the whole slice when it fits", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+		MArray_Add(&arr, 1)
+
+		n, err := MArray_AppendCopy(&arr, []int{2, 3, 4})
+		if err != nil || n != 3 {
+			t.Fatalf("expected 3 appended without error, got %d, %v", n, err)
+		}
+		if !slices.Equal(MArray_Snapshot(&arr), []int{1, 2, 3, 4}) {
+			t.Errorf("unexpected contents %v", MArray_Snapshot(&arr))
+		}
+	})
+
+	t.Run("partially appends when capacity is exceeded", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		MArray_Add(&arr, 1)
+
+		n, err := MArray_AppendCopy(&arr, []int{2, 3, 4, 5, 6})
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+		if n != 3 {
+			t.Errorf("expected 3 appended, got %d", n)
+		}
+		if !slices.Equal(MArray_Snapshot(&arr), []int{1, 2, 3, 4}) {
+			t.Errorf("unexpected contents %v", MArray_Snapshot(&arr))
+		}
+
+		n, err = MArray_AppendCopy(&arr, []int{7})
+		if n != 0 || !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected nothing appended to a full array, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("empty source is a no-op", func(t *testing.T) {
+		arr := NewMemArray[int](0)
+
+		if n, err := MArray_AppendCopy(&arr, nil); n != 0 || err != nil {
+			t.Errorf("expected 0, nil, got %d, %v", n, err)
+		}
+	})
+
+	t.Run("growable arrays grow to fit", func(t *testing.T) {
+		arr := NewMemArray[int](2, MemArrayWithGrowable[int]())
+
+		n, err := MArray_AppendCopy(&arr, []int{1, 2, 3, 4, 5})
+		if err != nil || n != 5 {
+			t.Fatalf("expected 5 appended without error, got %d, %v", n, err)
+		}
+		if arr.Capacity() < 5 || !slices.Equal(MArray_Snapshot(&arr), []int{1, 2, 3, 4, 5}) {
+			t.Errorf("unexpected contents %v with capacity %d", MArray_Snapshot(&arr), arr.Capacity())
+		}
+	})
+}
+
+func BenchmarkMArray_AppendCopy(b *testing.B) {
+	src := make([]int, 1024)
+	for i := range src {
+		src[i] = i
+	}
+	arr := NewMemArray[int](int32(len(src)))
+
+	b.Run("AppendCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			arr.Truncate(0)
+			MArray_AppendCopy(&arr, src)
+		}
+	})
+
+	b.Run("Add", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			arr.Truncate(0)
+			for _, item := range src {
+				MArray_Add(&arr, item)
+			}
+		}
+	})
+}
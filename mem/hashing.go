@@ -1,6 +1,12 @@
 package mem
 
 import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -10,10 +16,45 @@ type HashElementId struct {
 	Offset   uint32
 	BaseId   uint32
 	StringId string // to recover the string from the hash
+	// components holds the individual parts added to a NewHashBuilderWithComponents builder,
+	// each encoded as "<length>:<part>". A string rather than a slice keeps HashElementId
+	// comparable with ==.
+	components string
+}
+
+// String formats the id for logging, e.g. HashElementId{Id: 1234, StringId: "node-5"}.
+// BaseId and Offset are included only when an offset was applied, i.e. BaseId differs from Id.
+func (id HashElementId) String() string {
+	if id.BaseId == id.Id {
+		return fmt.Sprintf("HashElementId{Id: %d, StringId: %q}", id.Id, id.StringId)
+	}
+	return fmt.Sprintf("HashElementId{Id: %d, BaseId: %d, Offset: %d, StringId: %q}", id.Id, id.BaseId, id.Offset, id.StringId)
+}
+
+// Parts returns the individual strings added to the builder, in order, for ids built
+// with NewHashBuilderWithComponents. It returns nil for other ids.
+func (id HashElementId) Parts() []string {
+	var parts []string
+	rest := id.components
+	for rest != "" {
+		separator := strings.IndexByte(rest, ':')
+		length, _ := strconv.Atoi(rest[:separator])
+		rest = rest[separator+1:]
+		parts = append(parts, rest[:length])
+		rest = rest[length:]
+	}
+	return parts
 }
 
 type HashingOptions struct {
 	StringIdJoiner func(string, string) string
+	// HashFunc, when set, replaces the built-in mixing with a standard hash.Hash32
+	// implementation (e.g. fnv.New32a). A non-zero seed, and anything mixed before the
+	// hash function was first supplied, is written into it as a 4-byte prefix.
+	HashFunc func() hash.Hash32
+	// DisableStringIdTracking skips the joiner entirely, leaving StringId untouched.
+	// Useful in hot paths where only the numeric Id is needed.
+	DisableStringIdTracking bool
 }
 
 var DefaultHashingOptions = HashingOptions{
@@ -28,87 +69,459 @@ func HashingOptionsWithJoiner(joiner func(string, string) string) HashingOptions
 	}
 }
 
+// HashingOptionsWithDelimiter returns options whose joiner puts sep between StringId
+// components, so "ab"+"c" and "a"+"bc" no longer produce the same StringId. No separator
+// is added while the StringId is still empty, i.e. before the first component.
+// Use HashingWithDelimiter to set it per call without resetting other options.
+func HashingOptionsWithDelimiter(sep string) HashingOptions {
+	return HashingOptionsWithJoiner(delimiterJoiner(sep))
+}
+
+func delimiterJoiner(sep string) func(string, string) string {
+	return func(a, b string) string {
+		if a == "" {
+			return b
+		}
+		return a + sep + b
+	}
+}
+
+// HashingOptionsWithHashFunc returns the default options with the built-in mixing
+// replaced by the hash.Hash32 produced by hashFunc. Use HashingWithHashFunc to set it per
+// call without resetting other options.
+func HashingOptionsWithHashFunc(hashFunc func() hash.Hash32) HashingOptions {
+	return HashingOptions{
+		StringIdJoiner: DefaultHashingOptions.StringIdJoiner,
+		HashFunc:       hashFunc,
+	}
+}
+
 type HashingOption func(*HashingOptions)
 
+// HashingWithStringIdTracking enables or disables building up the StringId.
+func HashingWithStringIdTracking(enabled bool) HashingOption {
+	return func(o *HashingOptions) {
+		o.DisableStringIdTracking = !enabled
+	}
+}
+
+// HashingWithHashFunc replaces the built-in mixing with the hash.Hash32 produced by hashFunc,
+// like HashingOptionsWithHashFunc, leaving the other options untouched.
+func HashingWithHashFunc(hashFunc func() hash.Hash32) HashingOption {
+	return func(o *HashingOptions) {
+		o.HashFunc = hashFunc
+	}
+}
+
+// HashingWithDelimiter joins StringId components with sep, like HashingOptionsWithDelimiter,
+// leaving the other options untouched.
+func HashingWithDelimiter(sep string) HashingOption {
+	return func(o *HashingOptions) {
+		o.StringIdJoiner = delimiterJoiner(sep)
+	}
+}
+
 type HashBuilder struct {
+	seed     uint32
 	hash     uint32
 	stringId string
+	// offset is added to the BaseId to derive the final Id, see WithOffset.
+	offset uint32
+	// trackComponents records each added part separately, see NewHashBuilderWithComponents.
+	trackComponents bool
+	components      string
+	// hasher is created from HashingOptions.HashFunc the first time one is supplied.
+	// Once set, every byte is written to it instead of the inline mixing.
+	hasher hash.Hash32
+	// scratch holds the bytes of a single write to hasher, so AddByte and mixNumber
+	// don't allocate a slice per call.
+	scratch [4]byte
+	// hashFunc is the factory hasher was created with, kept so Clone can create another.
+	hashFunc func() hash.Hash32
+	// opts holds the options resolved for the current call. Keeping them on the
+	// builder avoids a heap allocation per Add* call.
+	opts HashingOptions
 }
 
 func NewHashBuilder(seed uint32) *HashBuilder {
-	return &HashBuilder{hash: seed, stringId: ""}
+	return &HashBuilder{seed: seed, hash: seed, stringId: ""}
+}
+
+// NewHashBuilderWithComponents returns a builder that also records every added string,
+// number, float and bool as a separate component, recoverable with HashElementId.Parts.
+// Unlike StringId, which concatenates the parts, this keeps their boundaries.
+func NewHashBuilderWithComponents(seed uint32) *HashBuilder {
+	return &HashBuilder{seed: seed, hash: seed, stringId: "", trackComponents: true}
+}
+
+// HashBuilderFromParent returns a builder for a child id, seeded with parent.Id and with its
+// StringId starting from parent.StringId, so children carry the parent's context.
+func HashBuilderFromParent(parent HashElementId) *HashBuilder {
+	return &HashBuilder{seed: parent.Id, hash: parent.Id, stringId: parent.StringId}
+}
+
+// HashBuilder can be used anywhere a standard hash.Hash32 or io.Writer is expected.
+var _ hash.Hash32 = (*HashBuilder)(nil)
+
+// Write feeds p through AddByte. It never returns an error.
+func (h *HashBuilder) Write(p []byte) (int, error) {
+	for _, b := range p {
+		h.AddByte(b)
+	}
+	return len(p), nil
+}
+
+// Sum32 returns the finalized Id, matching Build().Id.
+func (h *HashBuilder) Sum32() uint32 {
+	return h.Build().Id
+}
+
+// Sum appends the big-endian Sum32 to b.
+func (h *HashBuilder) Sum(b []byte) []byte {
+	s := h.Sum32()
+	return append(b, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+// Reset restores the builder to its seed, clearing the StringId.
+func (h *HashBuilder) Reset() {
+	h.hash = h.seed
+	h.stringId = ""
+	h.offset = 0
+	h.components = ""
+	if h.hasher != nil {
+		h.hasher.Reset()
+		h.carryInlineState()
+	}
 }
 
+func (h *HashBuilder) Size() int {
+	return 4
+}
+
+func (h *HashBuilder) BlockSize() int {
+	return 1
+}
+
+// Clone returns an independent copy of the builder's current state, so a common prefix can be
+// hashed once and then branched, e.g. base.Clone().AddString("a") and base.Clone().AddString("b").
+// A pluggable hasher is copied through encoding.BinaryMarshaler, which the standard library
+// hashes implement; Clone panics if the hasher's state cannot be copied that way.
+func (h *HashBuilder) Clone() *HashBuilder {
+	clone := *h
+	if h.hasher == nil {
+		return &clone
+	}
+
+	marshaler, ok := h.hasher.(encoding.BinaryMarshaler)
+	if !ok {
+		panic(fmt.Sprintf("HashBuilder.Clone: hasher %T does not implement encoding.BinaryMarshaler", h.hasher))
+	}
+	state, err := marshaler.MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("HashBuilder.Clone: %v", err))
+	}
+	clone.hasher = h.hashFunc()
+	unmarshaler, ok := clone.hasher.(encoding.BinaryUnmarshaler)
+	if !ok {
+		panic(fmt.Sprintf("HashBuilder.Clone: hasher %T does not implement encoding.BinaryUnmarshaler", clone.hasher))
+	}
+	if err := unmarshaler.UnmarshalBinary(state); err != nil {
+		panic(fmt.Sprintf("HashBuilder.Clone: %v", err))
+	}
+	return &clone
+}
+
+// WithOffset makes Build derive the Id at offset from the un-offset BaseId, giving
+// stable per-index child ids (e.g. list items) that share a BaseId.
+func (h *HashBuilder) WithOffset(offset uint32) *HashBuilder {
+	h.offset = offset
+	return h
+}
+
+// AddBytes hashes the first length bytes of data. length is clamped to [0, len(data)], so an
+// externally provided length can never read past the slice.
 func (h *HashBuilder) AddBytes(data []byte, length int32) {
+	length = min(max(length, 0), int32(min(len(data), math.MaxInt32)))
 	for _, charByte := range data[:length] {
 		h.AddByte(charByte)
 	}
 }
 func (h *HashBuilder) AddByte(data byte) *HashBuilder {
+	if h.hasher != nil {
+		h.scratch[0] = data
+		h.hasher.Write(h.scratch[:1])
+		return h
+	}
 	h.hash += uint32(data)
 	h.hash += (h.hash << 10)
 	h.hash ^= (h.hash >> 6)
 	return h
 }
 func (h *HashBuilder) AddString(key string, options ...HashingOption) *HashBuilder {
-	opts := DefaultHashingOptions
-	for _, option := range options {
-		option(&opts)
+	opts := h.resolveOptions(options)
+	for i := 0; i < len(key); i++ {
+		h.AddByte(key[i])
 	}
-	stringBytes := []byte(key)
-	for _, charByte := range stringBytes {
-		h.AddByte(charByte)
+	if h.tracksParts(opts) {
+		h.recordPart(opts, key)
 	}
-	h.stringId = opts.StringIdJoiner(h.stringId, key)
 	return h
 }
 func (h *HashBuilder) AddNumber(number uint32, options ...HashingOption) *HashBuilder {
-	opts := DefaultHashingOptions
-	for _, option := range options {
-		option(&opts)
+	opts := h.resolveOptions(options)
+	h.mixNumber(number)
+
+	if h.tracksParts(opts) {
+		h.recordPart(opts, strconv.Itoa(int(number)))
+	}
+	return h
+}
+
+// AddUint64 feeds the low 32 bits of n through the same mixing as AddNumber, followed by
+// the high 32 bits when they are non-zero. Values below 2^32 therefore hash exactly like
+// AddNumber. The StringId records the decimal value.
+func (h *HashBuilder) AddUint64(n uint64, options ...HashingOption) *HashBuilder {
+	opts := h.resolveOptions(options)
+	h.mixNumber(uint32(n))
+	if high := uint32(n >> 32); high != 0 {
+		h.mixNumber(high)
+	}
+
+	if h.tracksParts(opts) {
+		h.recordPart(opts, strconv.FormatUint(n, 10))
+	}
+	return h
+}
+
+// tracksParts reports whether added values need to be formatted as strings at all.
+func (h *HashBuilder) tracksParts(opts *HashingOptions) bool {
+	return !opts.DisableStringIdTracking || h.trackComponents
+}
+
+// recordPart joins part onto the StringId and, for component builders, records it separately.
+func (h *HashBuilder) recordPart(opts *HashingOptions, part string) {
+	if !opts.DisableStringIdTracking {
+		h.stringId = opts.StringIdJoiner(h.stringId, part)
+	}
+	if h.trackComponents {
+		h.components += strconv.Itoa(len(part)) + ":" + part
+	}
+}
+
+// mixNumber feeds a 32-bit number into the hash state without touching the StringId.
+func (h *HashBuilder) mixNumber(number uint32) {
+	if h.hasher != nil {
+		binary.LittleEndian.PutUint32(h.scratch[:], number)
+		h.hasher.Write(h.scratch[:])
+		return
 	}
 	h.hash += (number + 48)
 	h.hash += (h.hash << 10)
 	h.hash ^= (h.hash >> 6)
+}
 
-	h.stringId = opts.StringIdJoiner(h.stringId, strconv.Itoa(int(number)))
+// AddFloat feeds the IEEE-754 bits of f (little-endian) through AddByte.
+func (h *HashBuilder) AddFloat(f float64, options ...HashingOption) *HashBuilder {
+	opts := h.resolveOptions(options)
+	bits := math.Float64bits(f)
+	for i := 0; i < 8; i++ {
+		h.AddByte(byte(bits >> (8 * i)))
+	}
+	if h.tracksParts(opts) {
+		h.recordPart(opts, strconv.FormatFloat(f, 'g', -1, 64))
+	}
 	return h
 }
 
+// AddBool feeds a single byte (1 for true, 0 for false) through AddByte.
+func (h *HashBuilder) AddBool(b bool, options ...HashingOption) *HashBuilder {
+	opts := h.resolveOptions(options)
+	if b {
+		h.AddByte(1)
+	} else {
+		h.AddByte(0)
+	}
+	if h.tracksParts(opts) {
+		h.recordPart(opts, strconv.FormatBool(b))
+	}
+	return h
+}
+
+// AddNumbers is equivalent to calling AddNumber for each number, but resolves the options
+// once. With StringId tracking disabled it only mixes, without formatting or allocating.
 func (h *HashBuilder) AddNumbers(numbers []uint32, options ...HashingOption) *HashBuilder {
-	opts := DefaultHashingOptions
-	for _, option := range options {
-		option(&opts)
+	opts := h.resolveOptions(options)
+	if !h.tracksParts(opts) {
+		for _, number := range numbers {
+			h.mixNumber(number)
+		}
+		return h
 	}
 	for _, number := range numbers {
-		h.AddNumber(number, options...)
+		h.mixNumber(number)
+		h.recordPart(opts, strconv.Itoa(int(number)))
 	}
 	return h
 }
 
-func (h *HashBuilder) build() HashElementId {
+// resolveOptions applies options over DefaultHashingOptions and activates a HashFunc if one is set.
+func (h *HashBuilder) resolveOptions(options []HashingOption) *HashingOptions {
+	h.opts = DefaultHashingOptions
+	for _, option := range options {
+		option(&h.opts)
+	}
+	h.useHashFunc(h.opts)
+	return &h.opts
+}
+
+// useHashFunc switches the builder to opts.HashFunc if one is set and no hasher is active yet.
+func (h *HashBuilder) useHashFunc(opts HashingOptions) {
+	if h.hasher == nil && opts.HashFunc != nil {
+		h.hasher = opts.HashFunc()
+		h.hashFunc = opts.HashFunc
+		h.carryInlineState()
+	}
+}
+
+// carryInlineState writes the inline hash state into a freshly started or reset hasher, so
+// the seed and any bytes mixed before the switch still affect the result. An unseeded builder
+// with nothing mixed yet writes nothing and therefore matches the plain hash function.
+func (h *HashBuilder) carryInlineState() {
+	if h.hash != 0 {
+		binary.LittleEndian.PutUint32(h.scratch[:], h.hash)
+		h.hasher.Write(h.scratch[:])
+	}
+}
+
+// sum returns the current hash state, either from the pluggable hasher or the inline mix.
+func (h *HashBuilder) sum() uint32 {
+	if h.hasher != nil {
+		return h.hasher.Sum32()
+	}
+	return h.hash
+}
+
+// Build finalizes the builder into a HashElementId. It can be used to hash custom
+// sequences, e.g. NewHashBuilder(seed).AddString("node").AddNumber(42).Build().
+func (h *HashBuilder) Build() HashElementId {
 
-	hash := h.hash
+	hash := h.sum()
 	hash += (hash << 3)
 	hash ^= (hash >> 11)
 	hash += (hash << 15)
 
+	baseId := h.sum() + 1
 	return HashElementId{
-		Id:       h.hash + 1,
-		Offset:   0,
-		BaseId:   h.hash + 1,
-		StringId: h.stringId,
+		Id:         baseId + h.offset,
+		Offset:     h.offset,
+		BaseId:     baseId,
+		StringId:   h.stringId,
+		components: h.components,
 	}
 }
 
 func HashString(key string, seed uint32, options ...HashingOption) HashElementId {
-	return NewHashBuilder(seed).AddString(key, options...).build()
+	return NewHashBuilder(seed).AddString(key, options...).Build()
 }
 
 func HashNumber(number uint32, seed uint32, options ...HashingOption) HashElementId {
-	return NewHashBuilder(seed).AddNumber(number, options...).build()
+	return NewHashBuilder(seed).AddNumber(number, options...).Build()
+}
+
+func HashUint64(n uint64, seed uint32, options ...HashingOption) HashElementId {
+	return NewHashBuilder(seed).AddUint64(n, options...).Build()
 }
 
 func HashManyNumbers(seed uint32, numbers []uint32, options ...HashingOption) HashElementId {
-	return NewHashBuilder(seed).AddNumbers(numbers, options...).build()
+	return NewHashBuilder(seed).AddNumbers(numbers, options...).Build()
+}
+
+// HashNumberSet hashes numbers without regard to their order, so permutations of the same
+// numbers produce the same Id, e.g. for keys derived from a set of selected ids. Each number is
+// mixed on its own and the results are summed, which commutes; the sum and the count are then
+// mixed into the Id. Duplicates count, so {1, 1} and {1} differ. The StringId is left empty.
+func HashNumberSet(seed uint32, numbers []uint32) HashElementId {
+	var sum uint32
+	for _, number := range numbers {
+		sum += mixSetElement(seed, number)
+	}
+	builder := NewHashBuilder(seed)
+	builder.mixNumber(sum)
+	builder.mixNumber(uint32(len(numbers)))
+	return builder.Build()
+}
+
+// mixSetElement hashes a single HashNumberSet element, with a final avalanche so that summing
+// the results does not let nearby numbers cancel out.
+func mixSetElement(seed uint32, number uint32) uint32 {
+	element := HashBuilder{hash: seed}
+	element.mixNumber(number)
+	hash := element.hash
+	hash += (hash << 3)
+	hash ^= (hash >> 11)
+	hash += (hash << 15)
+	return hash
+}
+
+// HashReader hashes the bytes of r like HashString hashes a string, reading the stream in
+// chunks so large inputs are never held in memory at once. The StringId is left empty.
+// On a read error it returns the zero HashElementId and the error.
+func HashReader(r io.Reader, seed uint32) (HashElementId, error) {
+	builder := NewHashBuilder(seed)
+	if _, err := io.Copy(builder, r); err != nil {
+		return HashElementId{}, err
+	}
+	return builder.Build(), nil
+}
+
+// HashCombine derives a composite id for hierarchical keys by seeding a builder with the
+// parent's Id and feeding it the little-endian bytes of the child's Id. The StringId joins
+// both with the default joiner.
+func HashCombine(parent HashElementId, child HashElementId) HashElementId {
+	builder := NewHashBuilder(parent.Id)
+	builder.Write([]byte{byte(child.Id), byte(child.Id >> 8), byte(child.Id >> 16), byte(child.Id >> 24)})
+	builder.stringId = DefaultHashingOptions.StringIdJoiner(parent.StringId, child.StringId)
+	return builder.Build()
+}
+
+// HashMemArray hashes the populated region of array in order, feeding each element's bytes
+// from toBytes through a HashBuilder, e.g. to detect whether an array changed between frames.
+// Each element is prefixed with its byte length so that element boundaries affect the hash.
+func HashMemArray[T any](array *MemArray[T], seed uint32, toBytes func(T) []byte) HashElementId {
+	builder := NewHashBuilder(seed)
+	for _, item := range MArray_ToSlice(array) {
+		data := toBytes(item)
+		length := uint32(len(data))
+		builder.Write([]byte{byte(length), byte(length >> 8), byte(length >> 16), byte(length >> 24)})
+		builder.Write(data)
+	}
+	return builder.Build()
+}
+
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// HashStringFNV returns the standard 32-bit FNV-1a hash of key, so ids can be reproduced
+// by other languages. It is unrelated to HashString, which uses the builder's own mixing.
+func HashStringFNV(key string) uint32 {
+	hash := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= fnvPrime32
+	}
+	return hash
+}
+
+// HashStringFNV64 returns the standard 64-bit FNV-1a hash of key.
+func HashStringFNV64(key string) uint64 {
+	hash := uint64(fnvOffset64)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint64(key[i])
+		hash *= fnvPrime64
+	}
+	return hash
 }
@@ -1,7 +1,14 @@
 package mem
 
 import (
+	"bytes"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestNewHashBuilder(t *testing.T) {
@@ -102,6 +109,29 @@ func TestHashBuilder_AddBytes(t *testing.T) {
 			t.Errorf("expected hash to remain unchanged for empty slice, got %d vs %d", builder.hash, initialHash)
 		}
 	})
+
+	t.Run("clamps a length beyond the slice", func(t *testing.T) {
+		data := []byte{65, 66, 67}
+		builder1 := NewHashBuilder(0)
+		builder1.AddBytes(data, 10)
+
+		builder2 := NewHashBuilder(0)
+		builder2.AddBytes(data, 3)
+
+		if builder1.hash != builder2.hash {
+			t.Errorf("expected length to be clamped to the slice, got %d vs %d", builder1.hash, builder2.hash)
+		}
+	})
+
+	t.Run("treats a negative length as zero", func(t *testing.T) {
+		builder := NewHashBuilder(42)
+		initialHash := builder.hash
+		builder.AddBytes([]byte{65, 66}, -1)
+
+		if builder.hash != initialHash {
+			t.Errorf("expected hash to remain unchanged, got %d vs %d", builder.hash, initialHash)
+		}
+	})
 }
 
 func TestHashBuilder_AddString(t *testing.T) {
@@ -303,11 +333,11 @@ func TestHashBuilder_AddNumbers(t *testing.T) {
 	})
 }
 
-func TestHashBuilder_build(t *testing.T) {
+func TestHashBuilder_Build(t *testing.T) {
 	t.Run("builds HashElementId", func(t *testing.T) {
 		builder := NewHashBuilder(0)
 		builder.AddString("test")
-		result := builder.build()
+		result := builder.Build()
 
 		if result.Id == 0 {
 			t.Error("expected non-zero Id")
@@ -326,11 +356,11 @@ func TestHashBuilder_build(t *testing.T) {
 	t.Run("produces consistent results", func(t *testing.T) {
 		builder1 := NewHashBuilder(0)
 		builder1.AddString("test")
-		result1 := builder1.build()
+		result1 := builder1.Build()
 
 		builder2 := NewHashBuilder(0)
 		builder2.AddString("test")
-		result2 := builder2.build()
+		result2 := builder2.Build()
 
 		if result1.Id != result2.Id {
 			t.Errorf("expected consistent Id, got %d vs %d", result1.Id, result2.Id)
@@ -340,10 +370,34 @@ func TestHashBuilder_build(t *testing.T) {
 		}
 	})
 
+	t.Run("builds composite keys from chained calls", func(t *testing.T) {
+		result := NewHashBuilder(3).AddString("node").AddNumber(42).Build()
+
+		expected := NewHashBuilder(3)
+		expected.AddString("node")
+		expected.AddNumber(42)
+
+		if result.Id != expected.Build().Id {
+			t.Errorf("expected Id = %d, got %d", expected.Build().Id, result.Id)
+		}
+		if result.StringId != "node42" {
+			t.Errorf("expected StringId = %q, got %q", "node42", result.StringId)
+		}
+	})
+
+	t.Run("wrappers delegate to Build", func(t *testing.T) {
+		if HashString("test", 5).Id != NewHashBuilder(5).AddString("test").Build().Id {
+			t.Error("expected HashString to match Build")
+		}
+		if HashNumber(9, 5).Id != NewHashBuilder(5).AddNumber(9).Build().Id {
+			t.Error("expected HashNumber to match Build")
+		}
+	})
+
 	t.Run("Id and BaseId are set correctly", func(t *testing.T) {
 		builder := NewHashBuilder(0)
 		builder.AddString("test")
-		result := builder.build()
+		result := builder.Build()
 
 		if result.Id == 0 {
 			t.Error("expected non-zero Id")
@@ -502,7 +556,7 @@ func TestHashManyNumbers(t *testing.T) {
 
 		builder1 := NewHashBuilder(0)
 		builder1.AddNumbers(numbers)
-		result1 := builder1.build()
+		result1 := builder1.Build()
 
 		result2 := HashManyNumbers(0, numbers)
 
@@ -513,6 +567,40 @@ func TestHashManyNumbers(t *testing.T) {
 			t.Errorf("expected same StringId, got %q vs %q", result1.StringId, result2.StringId)
 		}
 	})
+
+	t.Run("resolves options once per call", func(t *testing.T) {
+		calls := 0
+		countingJoiner := func(opts *HashingOptions) {
+			calls++
+			opts.StringIdJoiner = func(a, b string) string { return a + "," + b }
+		}
+
+		result := HashManyNumbers(0, []uint32{1, 2, 3, 4}, countingJoiner)
+
+		if calls != 1 {
+			t.Errorf("expected option to be applied once, got %d", calls)
+		}
+		if result.StringId != ",1,2,3,4" {
+			t.Errorf("expected joiner to apply to every element, got %q", result.StringId)
+		}
+	})
+
+	t.Run("matches individual AddNumber calls with the same options", func(t *testing.T) {
+		joiner := func(opts *HashingOptions) {
+			opts.StringIdJoiner = func(a, b string) string { return a + "/" + b }
+		}
+		numbers := []uint32{7, 8, 9}
+
+		builder := NewHashBuilder(3)
+		for _, number := range numbers {
+			builder.AddNumber(number, joiner)
+		}
+		expected := builder.Build()
+
+		if result := HashManyNumbers(3, numbers, joiner); result != expected {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
 }
 
 func TestHashingOptionsWithJoiner(t *testing.T) {
@@ -533,6 +621,49 @@ func TestHashingOptionsWithJoiner(t *testing.T) {
 	})
 }
 
+func TestHashingOptionsWithDelimiter(t *testing.T) {
+	dotOption := HashingWithDelimiter(".")
+
+	t.Run("joins components with the delimiter", func(t *testing.T) {
+		result := NewHashBuilder(0).AddString("a", dotOption).AddString("b", dotOption).AddString("c", dotOption).Build()
+
+		if result.StringId != "a.b.c" {
+			t.Errorf("expected StringId = %q, got %q", "a.b.c", result.StringId)
+		}
+	})
+
+	t.Run("single component has no leading delimiter", func(t *testing.T) {
+		result := NewHashBuilder(0).AddString("a", dotOption).Build()
+
+		if result.StringId != "a" {
+			t.Errorf("expected StringId = %q, got %q", "a", result.StringId)
+		}
+	})
+
+	t.Run("disambiguates differently split components", func(t *testing.T) {
+		first := NewHashBuilder(0).AddString("ab", dotOption).AddString("c", dotOption).Build()
+		second := NewHashBuilder(0).AddString("a", dotOption).AddString("bc", dotOption).Build()
+
+		if first.StringId == second.StringId {
+			t.Errorf("expected different StringIds, both were %q", first.StringId)
+		}
+	})
+
+	t.Run("keeps options applied before it", func(t *testing.T) {
+		result := NewHashBuilder(0).AddString("a", HashingWithStringIdTracking(false), dotOption).Build()
+
+		if result.StringId != "" {
+			t.Errorf("expected StringId tracking to stay disabled, got %q", result.StringId)
+		}
+	})
+
+	t.Run("HashingOptionsWithDelimiter builds the same joiner", func(t *testing.T) {
+		if joined := HashingOptionsWithDelimiter(".").StringIdJoiner("a", "b"); joined != "a.b" {
+			t.Errorf("expected %q, got %q", "a.b", joined)
+		}
+	})
+}
+
 func TestDefaultHashingOptions(t *testing.T) {
 	t.Run("has default joiner that concatenates", func(t *testing.T) {
 		result := DefaultHashingOptions.StringIdJoiner("hello", "world")
@@ -541,3 +672,931 @@ func TestDefaultHashingOptions(t *testing.T) {
 		}
 	})
 }
+
+func TestHashingOptionsWithHashFunc(t *testing.T) {
+	fnvOption := HashingWithHashFunc(func() hash.Hash32 { return fnv.New32a() })
+
+	t.Run("creates options with hash func and default joiner", func(t *testing.T) {
+		opts := HashingOptionsWithHashFunc(func() hash.Hash32 { return fnv.New32a() })
+
+		if opts.HashFunc == nil {
+			t.Fatal("expected HashFunc to be set")
+		}
+		if opts.StringIdJoiner == nil {
+			t.Fatal("expected StringIdJoiner to be set")
+		}
+	})
+
+	t.Run("FNV-1a matches hash/fnv over the same byte stream", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("hello", fnvOption).AddByte('!').AddString("world", fnvOption)
+		result := builder.Build()
+
+		reference := fnv.New32a()
+		reference.Write([]byte("hello!world"))
+
+		if result.Id != reference.Sum32()+1 {
+			t.Errorf("expected Id = %d, got %d", reference.Sum32()+1, result.Id)
+		}
+		if result.StringId != "helloworld" {
+			t.Errorf("expected StringId = %q, got %q", "helloworld", result.StringId)
+		}
+	})
+
+	t.Run("numbers are written as little-endian bytes", func(t *testing.T) {
+		result := HashNumber(0x01020304, 0, fnvOption)
+
+		reference := fnv.New32a()
+		reference.Write([]byte{0x04, 0x03, 0x02, 0x01})
+
+		if result.Id != reference.Sum32()+1 {
+			t.Errorf("expected Id = %d, got %d", reference.Sum32()+1, result.Id)
+		}
+	})
+
+	t.Run("seed changes the Id", func(t *testing.T) {
+		if HashString("x", 1, fnvOption).Id == HashString("x", 999, fnvOption).Id {
+			t.Error("expected different seeds to give different Ids")
+		}
+
+		reference := fnv.New32a()
+		reference.Write([]byte{1, 0, 0, 0, 'x'})
+		if id := HashString("x", 1, fnvOption).Id; id != reference.Sum32()+1 {
+			t.Errorf("expected the seed to be written as a little-endian prefix, got %d, want %d", id, reference.Sum32()+1)
+		}
+	})
+
+	t.Run("parent seeds the child Id", func(t *testing.T) {
+		first := HashBuilderFromParent(HashString("p1", 0)).AddString("child", fnvOption).Build()
+		second := HashBuilderFromParent(HashString("p2", 0)).AddString("child", fnvOption).Build()
+
+		if first.Id == second.Id {
+			t.Error("expected children of different parents to have different Ids")
+		}
+	})
+
+	t.Run("bytes mixed before switching to the hasher are kept", func(t *testing.T) {
+		first := NewHashBuilder(0).AddString("a").AddString("b", fnvOption).Build()
+		second := NewHashBuilder(0).AddString("zzz").AddString("b", fnvOption).Build()
+
+		if first.Id == second.Id {
+			t.Error("expected the prefix hashed before the switch to change the Id")
+		}
+	})
+
+	t.Run("Reset restores the seed", func(t *testing.T) {
+		builder := NewHashBuilder(7).AddString("x", fnvOption)
+		builder.Reset()
+		builder.AddString("x", fnvOption)
+
+		if builder.Build().Id != HashString("x", 7, fnvOption).Id {
+			t.Error("expected a reset builder to hash like a fresh one")
+		}
+	})
+
+	t.Run("adding bytes does not allocate", func(t *testing.T) {
+		builder := NewHashBuilder(0).AddString("", fnvOption)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			builder.AddByte('a')
+			builder.AddNumber(42, HashingWithStringIdTracking(false))
+		})
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+
+	t.Run("keeps options applied before it", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("hello", HashingWithStringIdTracking(false), fnvOption)
+
+		if builder.hasher == nil {
+			t.Error("expected the hasher to be active")
+		}
+		if builder.stringId != "" {
+			t.Errorf("expected StringId tracking to stay disabled, got %q", builder.stringId)
+		}
+	})
+
+	t.Run("default behavior uses inline mixing", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("hello")
+
+		if builder.hasher != nil {
+			t.Error("expected no hasher without HashFunc option")
+		}
+		if builder.Build().Id != builder.hash+1 {
+			t.Error("expected Id to be derived from the inline hash")
+		}
+	})
+}
+
+func TestHashingWithStringIdTracking(t *testing.T) {
+	t.Run("disabled tracking leaves StringId empty", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("node", HashingWithStringIdTracking(false)).AddNumber(42, HashingWithStringIdTracking(false))
+
+		if builder.stringId != "" {
+			t.Errorf("expected empty stringId, got %q", builder.stringId)
+		}
+	})
+
+	t.Run("enabled tracking keeps StringId", func(t *testing.T) {
+		result := HashString("node", 0, HashingWithStringIdTracking(true))
+
+		if result.StringId != "node" {
+			t.Errorf("expected StringId = %q, got %q", "node", result.StringId)
+		}
+	})
+
+	t.Run("Id is unaffected by the tracking flag", func(t *testing.T) {
+		tracked := HashManyNumbers(7, []uint32{1, 2, 3})
+		untracked := HashManyNumbers(7, []uint32{1, 2, 3}, HashingWithStringIdTracking(false))
+
+		if tracked.Id != untracked.Id {
+			t.Errorf("expected same Id, got %d vs %d", tracked.Id, untracked.Id)
+		}
+		if untracked.StringId != "" {
+			t.Errorf("expected empty StringId, got %q", untracked.StringId)
+		}
+	})
+}
+
+func TestHashBuilder_AddNumbersFastPath(t *testing.T) {
+	numbers := make([]uint32, 10000)
+	for i := range numbers {
+		numbers[i] = uint32(i * 7)
+	}
+
+	t.Run("matches repeated AddNumber calls", func(t *testing.T) {
+		expected := NewHashBuilder(3)
+		for _, number := range numbers {
+			expected.AddNumber(number)
+		}
+
+		if result := NewHashBuilder(3).AddNumbers(numbers).Build(); result != expected.Build() {
+			t.Error("expected AddNumbers to match repeated AddNumber calls")
+		}
+	})
+
+	t.Run("matches repeated AddNumber calls without tracking", func(t *testing.T) {
+		option := HashingWithStringIdTracking(false)
+		expected := NewHashBuilder(3)
+		for _, number := range numbers {
+			expected.AddNumber(number, option)
+		}
+
+		if result := NewHashBuilder(3).AddNumbers(numbers, option).Build(); result != expected.Build() {
+			t.Error("expected AddNumbers to match repeated AddNumber calls")
+		}
+	})
+
+	t.Run("does not allocate without tracking", func(t *testing.T) {
+		option := HashingWithStringIdTracking(false)
+		builder := NewHashBuilder(0)
+
+		allocs := testing.AllocsPerRun(10, func() {
+			builder.AddNumbers(numbers, option)
+		})
+		if allocs != 0 {
+			t.Errorf("expected 0 allocations, got %v", allocs)
+		}
+	})
+}
+
+func BenchmarkHashBuilder_AddNumbers(b *testing.B) {
+	numbers := make([]uint32, 10000)
+	for i := range numbers {
+		numbers[i] = uint32(i)
+	}
+
+	b.Run("per-number AddNumber without tracking", func(b *testing.B) {
+		option := HashingWithStringIdTracking(false)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			builder := NewHashBuilder(0)
+			for _, number := range numbers {
+				builder.AddNumber(number, option)
+			}
+		}
+	})
+
+	b.Run("AddNumbers without tracking", func(b *testing.B) {
+		option := HashingWithStringIdTracking(false)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewHashBuilder(0).AddNumbers(numbers, option)
+		}
+	})
+
+	b.Run("AddNumbers with tracking", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewHashBuilder(0).AddNumbers(numbers)
+		}
+	})
+}
+
+func BenchmarkHashManyNumbers(b *testing.B) {
+	numbers := []uint32{10, 200, 3000, 40000, 500000}
+
+	b.Run("tracking", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			HashManyNumbers(0, numbers)
+		}
+	})
+
+	b.Run("no tracking", func(b *testing.B) {
+		option := HashingWithStringIdTracking(false)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			HashManyNumbers(0, numbers, option)
+		}
+	})
+}
+
+func TestHashBuilder_AddStringAllocations(t *testing.T) {
+	t.Run("hashing a string does not allocate", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		noTracking := HashingWithStringIdTracking(false)
+		key := "a reasonably long element key"
+
+		allocs := testing.AllocsPerRun(100, func() {
+			builder.AddString(key, noTracking)
+		})
+		if allocs != 0 {
+			t.Errorf("expected 0 allocations, got %v", allocs)
+		}
+	})
+}
+
+func BenchmarkHashBuilder_AddString(b *testing.B) {
+	builder := NewHashBuilder(0)
+	noTracking := HashingWithStringIdTracking(false)
+	key := "a reasonably long element key"
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		builder.AddString(key, noTracking)
+	}
+}
+
+func TestHashBuilder_AddFloat(t *testing.T) {
+	t.Run("adds float to hash", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		result := builder.AddFloat(0.5)
+
+		if result != builder {
+			t.Error("expected AddFloat to return the builder for chaining")
+		}
+		if builder.stringId != "0.5" {
+			t.Errorf("expected stringId = %q, got %q", "0.5", builder.stringId)
+		}
+	})
+
+	t.Run("produces different hashes for different floats", func(t *testing.T) {
+		values := []float64{0, 0.1, 0.2, 1, -1, 1e10}
+		seen := map[uint32]float64{}
+		for _, value := range values {
+			id := NewHashBuilder(0).AddFloat(value).Build().Id
+			if previous, ok := seen[id]; ok {
+				t.Errorf("expected distinct hashes, %v and %v collided", previous, value)
+			}
+			seen[id] = value
+		}
+	})
+
+	t.Run("produces stable results", func(t *testing.T) {
+		id1 := NewHashBuilder(0).AddFloat(3.25).Build().Id
+		id2 := NewHashBuilder(0).AddFloat(3.25).Build().Id
+
+		if id1 != id2 {
+			t.Errorf("expected consistent Id, got %d vs %d", id1, id2)
+		}
+	})
+
+	t.Run("feeds IEEE-754 bits through AddByte", func(t *testing.T) {
+		builder1 := NewHashBuilder(0)
+		builder1.AddFloat(1.0)
+
+		// 1.0 == 0x3FF0000000000000
+		builder2 := NewHashBuilder(0)
+		builder2.AddByte(0).AddByte(0).AddByte(0).AddByte(0).AddByte(0).AddByte(0).AddByte(0xF0).AddByte(0x3F)
+
+		if builder1.hash != builder2.hash {
+			t.Errorf("expected same hash, got %d vs %d", builder1.hash, builder2.hash)
+		}
+	})
+}
+
+func TestHashBuilder_AddBool(t *testing.T) {
+	t.Run("adds bool to hash", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		result := builder.AddBool(true)
+
+		if result != builder {
+			t.Error("expected AddBool to return the builder for chaining")
+		}
+		if builder.stringId != "true" {
+			t.Errorf("expected stringId = %q, got %q", "true", builder.stringId)
+		}
+	})
+
+	t.Run("produces different hashes for true and false", func(t *testing.T) {
+		trueId := NewHashBuilder(0).AddBool(true).Build().Id
+		falseId := NewHashBuilder(0).AddBool(false).Build().Id
+
+		if trueId == falseId {
+			t.Error("expected different hashes for true and false")
+		}
+	})
+
+	t.Run("produces stable results", func(t *testing.T) {
+		id1 := NewHashBuilder(0).AddString("visible").AddBool(false).Build()
+		id2 := NewHashBuilder(0).AddString("visible").AddBool(false).Build()
+
+		if id1.Id != id2.Id {
+			t.Errorf("expected consistent Id, got %d vs %d", id1.Id, id2.Id)
+		}
+		if id1.StringId != "visiblefalse" {
+			t.Errorf("expected StringId = %q, got %q", "visiblefalse", id1.StringId)
+		}
+	})
+}
+
+func TestHashBuilder_Hash32(t *testing.T) {
+	t.Run("Write matches repeated AddByte", func(t *testing.T) {
+		builder1 := NewHashBuilder(7)
+		n, err := builder1.Write([]byte("abc"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if n != 3 {
+			t.Errorf("expected n = 3, got %d", n)
+		}
+
+		builder2 := NewHashBuilder(7)
+		builder2.AddByte('a').AddByte('b').AddByte('c')
+
+		if builder1.hash != builder2.hash {
+			t.Errorf("expected same hash, got %d vs %d", builder1.hash, builder2.hash)
+		}
+	})
+
+	t.Run("works with io.Copy", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		if _, err := io.Copy(builder, bytes.NewReader([]byte("streamed"))); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if builder.Sum32() != HashString("streamed", 0).Id {
+			t.Errorf("expected Sum32 = %d, got %d", HashString("streamed", 0).Id, builder.Sum32())
+		}
+	})
+
+	t.Run("Sum32 matches the finalized Id", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("test")
+
+		if builder.Sum32() != builder.Build().Id {
+			t.Errorf("expected Sum32 = %d, got %d", builder.Build().Id, builder.Sum32())
+		}
+	})
+
+	t.Run("Sum appends big-endian Sum32", func(t *testing.T) {
+		builder := NewHashBuilder(0)
+		builder.AddString("test")
+		s := builder.Sum32()
+
+		result := builder.Sum([]byte{0xAA})
+		expected := []byte{0xAA, byte(s >> 24), byte(s >> 16), byte(s >> 8), byte(s)}
+		if !bytes.Equal(result, expected) {
+			t.Errorf("expected %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Reset restores the seed", func(t *testing.T) {
+		builder := NewHashBuilder(42)
+		builder.AddString("test")
+		builder.Reset()
+
+		if builder.hash != 42 {
+			t.Errorf("expected hash = 42, got %d", builder.hash)
+		}
+		if builder.stringId != "" {
+			t.Errorf("expected empty stringId, got %q", builder.stringId)
+		}
+	})
+
+	t.Run("reports size and block size", func(t *testing.T) {
+		var h hash.Hash32 = NewHashBuilder(0)
+
+		if h.Size() != 4 {
+			t.Errorf("expected Size = 4, got %d", h.Size())
+		}
+		if h.BlockSize() != 1 {
+			t.Errorf("expected BlockSize = 1, got %d", h.BlockSize())
+		}
+	})
+}
+
+func TestHashBuilder_WithOffset(t *testing.T) {
+	t.Run("offset 0 matches the default build", func(t *testing.T) {
+		expected := NewHashBuilder(0).AddString("list").Build()
+		result := NewHashBuilder(0).AddString("list").WithOffset(0).Build()
+
+		if result != expected {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+		if result.Offset != 0 || result.Id != result.BaseId {
+			t.Errorf("expected Offset 0 and Id == BaseId, got %+v", result)
+		}
+	})
+
+	t.Run("nonzero offsets derive ids from the same BaseId", func(t *testing.T) {
+		base := NewHashBuilder(0).AddString("list").Build()
+
+		for _, offset := range []uint32{1, 2, 100} {
+			result := NewHashBuilder(0).AddString("list").WithOffset(offset).Build()
+
+			if result.BaseId != base.BaseId {
+				t.Errorf("offset %d: expected BaseId %d, got %d", offset, base.BaseId, result.BaseId)
+			}
+			if result.Id != base.BaseId+offset {
+				t.Errorf("offset %d: expected Id %d, got %d", offset, base.BaseId+offset, result.Id)
+			}
+			if result.Offset != offset {
+				t.Errorf("expected Offset %d, got %d", offset, result.Offset)
+			}
+			if result.StringId != base.StringId {
+				t.Errorf("expected StringId %q, got %q", base.StringId, result.StringId)
+			}
+		}
+	})
+
+	t.Run("Reset clears the offset", func(t *testing.T) {
+		builder := NewHashBuilder(0).WithOffset(5)
+		builder.Reset()
+
+		if result := builder.Build(); result.Offset != 0 {
+			t.Errorf("expected Offset 0 after Reset, got %d", result.Offset)
+		}
+	})
+}
+
+func TestHashCombine(t *testing.T) {
+	t.Run("same inputs produce the same composite", func(t *testing.T) {
+		parent := HashString("panel", 0)
+		child := HashString("button", 0)
+
+		first := HashCombine(parent, child)
+		second := HashCombine(parent, child)
+
+		if first != second {
+			t.Errorf("expected deterministic result, got %+v and %+v", first, second)
+		}
+		if first.Id == parent.Id || first.Id == child.Id {
+			t.Error("expected composite id to differ from its parts")
+		}
+	})
+
+	t.Run("joins the string ids", func(t *testing.T) {
+		parent := HashString("panel", 0)
+		child := HashString("button", 0)
+
+		if result := HashCombine(parent, child); result.StringId != "panelbutton" {
+			t.Errorf("expected StringId %q, got %q", "panelbutton", result.StringId)
+		}
+	})
+
+	t.Run("different parents produce different composites", func(t *testing.T) {
+		child := HashString("button", 0)
+
+		first := HashCombine(HashString("panel", 0), child)
+		second := HashCombine(HashString("sidebar", 0), child)
+
+		if first.Id == second.Id {
+			t.Errorf("expected different ids, both were %d", first.Id)
+		}
+	})
+
+	t.Run("order of parent and child matters", func(t *testing.T) {
+		a := HashString("a", 0)
+		b := HashString("b", 0)
+
+		if HashCombine(a, b).Id == HashCombine(b, a).Id {
+			t.Error("expected combine to depend on which id is the parent")
+		}
+	})
+}
+
+func TestHashMemArray(t *testing.T) {
+	int32Bytes := func(v int32) []byte {
+		return []byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)}
+	}
+	newArray := func(values ...int32) MemArray[int32] {
+		arr := NewMemArray[int32](8)
+		for _, v := range values {
+			MArray_Add(&arr, v)
+		}
+		return arr
+	}
+
+	t.Run("identical arrays hash equal", func(t *testing.T) {
+		a := newArray(1, 2, 3)
+		b := newArray(1, 2, 3)
+
+		if HashMemArray(&a, 0, int32Bytes) != HashMemArray(&b, 0, int32Bytes) {
+			t.Error("expected identical arrays to hash equal")
+		}
+	})
+
+	t.Run("a single element change alters the hash", func(t *testing.T) {
+		arr := newArray(1, 2, 3)
+		before := HashMemArray(&arr, 0, int32Bytes)
+
+		MArray_Set(&arr, 1, 20)
+
+		if HashMemArray(&arr, 0, int32Bytes).Id == before.Id {
+			t.Error("expected hash to change after modifying an element")
+		}
+	})
+
+	t.Run("ignores the capacity tail", func(t *testing.T) {
+		a := newArray(1, 2)
+		b := newArray(1, 2, 3)
+		MArray_Pop(&b)
+
+		if HashMemArray(&a, 0, int32Bytes) != HashMemArray(&b, 0, int32Bytes) {
+			t.Error("expected stale values beyond Length not to affect the hash")
+		}
+	})
+
+	t.Run("element boundaries affect the hash", func(t *testing.T) {
+		a := NewMemArray[string](2)
+		MArray_Add(&a, "ab")
+		MArray_Add(&a, "c")
+		b := NewMemArray[string](2)
+		MArray_Add(&b, "a")
+		MArray_Add(&b, "bc")
+		stringBytes := func(s string) []byte { return []byte(s) }
+
+		if HashMemArray(&a, 0, stringBytes).Id == HashMemArray(&b, 0, stringBytes).Id {
+			t.Error("expected [ab c] and [a bc] to hash differently")
+		}
+	})
+
+	t.Run("seed changes the hash", func(t *testing.T) {
+		arr := newArray(1, 2, 3)
+
+		if HashMemArray(&arr, 0, int32Bytes).Id == HashMemArray(&arr, 1, int32Bytes).Id {
+			t.Error("expected different seeds to produce different hashes")
+		}
+	})
+}
+
+func TestHashUint64(t *testing.T) {
+	t.Run("distinct values hash distinctly", func(t *testing.T) {
+		values := []uint64{0, 1, 1 << 32, 1<<32 + 1, 1 << 63, ^uint64(0), 0xDEADBEEF00000000, 0x00000000DEADBEEF}
+		seen := make(map[uint32]uint64)
+
+		for _, v := range values {
+			id := HashUint64(v, 0).Id
+			if other, ok := seen[id]; ok {
+				t.Errorf("values %d and %d hash to the same id %d", v, other, id)
+			}
+			seen[id] = v
+		}
+	})
+
+	t.Run("values below 2^32 match AddNumber", func(t *testing.T) {
+		for _, v := range []uint32{0, 1, 42, 1<<32 - 1} {
+			expected := HashNumber(v, 7)
+			result := HashUint64(uint64(v), 7)
+
+			if result != expected {
+				t.Errorf("value %d: expected %+v, got %+v", v, expected, result)
+			}
+		}
+	})
+
+	t.Run("records the decimal StringId", func(t *testing.T) {
+		result := HashUint64(1<<40, 0)
+
+		if result.StringId != "1099511627776" {
+			t.Errorf("expected StringId %q, got %q", "1099511627776", result.StringId)
+		}
+	})
+
+	t.Run("feeds both halves to a pluggable hasher", func(t *testing.T) {
+		withHashFunc := func(o *HashingOptions) { o.HashFunc = fnv.New32a }
+
+		low := HashUint64(5, 0, withHashFunc)
+		wide := HashUint64(5|1<<32, 0, withHashFunc)
+
+		if low.Id == wide.Id {
+			t.Error("expected the high half to affect the hash")
+		}
+	})
+}
+
+func TestHashStringFNV(t *testing.T) {
+	t.Run("matches published 32-bit FNV-1a vectors", func(t *testing.T) {
+		vectors := map[string]uint32{
+			"":       0x811c9dc5,
+			"a":      0xe40c292c,
+			"foobar": 0xbf9cf968,
+		}
+		for key, expected := range vectors {
+			if got := HashStringFNV(key); got != expected {
+				t.Errorf("%q: expected %#x, got %#x", key, expected, got)
+			}
+		}
+	})
+
+	t.Run("matches published 64-bit FNV-1a vectors", func(t *testing.T) {
+		vectors := map[string]uint64{
+			"":       0xcbf29ce484222325,
+			"a":      0xaf63dc4c8601ec8c,
+			"foobar": 0x85944171f73967e8,
+		}
+		for key, expected := range vectors {
+			if got := HashStringFNV64(key); got != expected {
+				t.Errorf("%q: expected %#x, got %#x", key, expected, got)
+			}
+		}
+	})
+
+	t.Run("agrees with hash/fnv", func(t *testing.T) {
+		for _, key := range []string{"button", "panel/header", "élément"} {
+			h32 := fnv.New32a()
+			io.WriteString(h32, key)
+			h64 := fnv.New64a()
+			io.WriteString(h64, key)
+
+			if HashStringFNV(key) != h32.Sum32() {
+				t.Errorf("%q: 32-bit mismatch", key)
+			}
+			if HashStringFNV64(key) != h64.Sum64() {
+				t.Errorf("%q: 64-bit mismatch", key)
+			}
+		}
+	})
+}
+
+func TestHashElementId_Parts(t *testing.T) {
+	t.Run("recovers each component", func(t *testing.T) {
+		result := NewHashBuilderWithComponents(0).
+			AddString("panel").
+			AddNumber(42).
+			AddString("label:text").
+			Build()
+
+		parts := result.Parts()
+		expected := []string{"panel", "42", "label:text"}
+		if len(parts) != len(expected) {
+			t.Fatalf("expected %d parts, got %v", len(expected), parts)
+		}
+		for i := range expected {
+			if parts[i] != expected[i] {
+				t.Errorf("part %d: expected %q, got %q", i, expected[i], parts[i])
+			}
+		}
+		if result.StringId != "panel42label:text" {
+			t.Errorf("expected StringId %q, got %q", "panel42label:text", result.StringId)
+		}
+	})
+
+	t.Run("hash matches a regular builder", func(t *testing.T) {
+		plain := NewHashBuilder(7).AddString("a").AddNumber(1).Build()
+		tracked := NewHashBuilderWithComponents(7).AddString("a").AddNumber(1).Build()
+
+		if plain.Id != tracked.Id || plain.StringId != tracked.StringId {
+			t.Errorf("expected the same Id and StringId, got %+v and %+v", plain, tracked)
+		}
+	})
+
+	t.Run("keeps empty components", func(t *testing.T) {
+		parts := NewHashBuilderWithComponents(0).AddString("").AddString("x").Build().Parts()
+
+		if len(parts) != 2 || parts[0] != "" || parts[1] != "x" {
+			t.Errorf("expected [\"\" \"x\"], got %q", parts)
+		}
+	})
+
+	t.Run("records components even without StringId tracking", func(t *testing.T) {
+		result := NewHashBuilderWithComponents(0).
+			AddString("a", HashingWithStringIdTracking(false)).
+			AddNumber(1, HashingWithStringIdTracking(false)).
+			Build()
+
+		if result.StringId != "" {
+			t.Errorf("expected empty StringId, got %q", result.StringId)
+		}
+		if parts := result.Parts(); len(parts) != 2 || parts[0] != "a" || parts[1] != "1" {
+			t.Errorf("expected [a 1], got %q", parts)
+		}
+	})
+
+	t.Run("regular builders have no parts", func(t *testing.T) {
+		if parts := HashString("a", 0).Parts(); parts != nil {
+			t.Errorf("expected nil parts, got %q", parts)
+		}
+	})
+
+	t.Run("ids stay comparable", func(t *testing.T) {
+		a := NewHashBuilderWithComponents(0).AddString("x").AddString("y").Build()
+		b := NewHashBuilderWithComponents(0).AddString("x").AddString("y").Build()
+		c := NewHashBuilderWithComponents(0).AddString("xy").Build()
+
+		if a != b {
+			t.Error("expected equal builds to compare equal")
+		}
+		if a == c {
+			t.Error("expected different components to compare unequal")
+		}
+	})
+}
+
+func TestHashBuilderFromParent(t *testing.T) {
+	t.Run("child ids are stable and depend on the parent", func(t *testing.T) {
+		parent := HashString("panel", 0)
+		other := HashString("sidebar", 0)
+
+		first := HashBuilderFromParent(parent).AddString("button").Build()
+		second := HashBuilderFromParent(parent).AddString("button").Build()
+		third := HashBuilderFromParent(other).AddString("button").Build()
+
+		if first != second {
+			t.Errorf("expected stable ids, got %+v and %+v", first, second)
+		}
+		if first.Id == third.Id {
+			t.Error("expected different parents to produce different child ids")
+		}
+	})
+
+	t.Run("matches a builder seeded with the parent id", func(t *testing.T) {
+		parent := HashString("panel", 0)
+
+		child := HashBuilderFromParent(parent).AddNumber(3).Build()
+
+		if expected := NewHashBuilder(parent.Id).AddNumber(3).Build(); child.Id != expected.Id {
+			t.Errorf("expected Id %d, got %d", expected.Id, child.Id)
+		}
+	})
+
+	t.Run("inherits the parent's StringId prefix", func(t *testing.T) {
+		parent := HashString("panel", 0)
+
+		child := HashBuilderFromParent(parent).AddString("button").Build()
+
+		if child.StringId != "panelbutton" {
+			t.Errorf("expected StringId %q, got %q", "panelbutton", child.StringId)
+		}
+	})
+}
+
+func TestHashBuilder_Clone(t *testing.T) {
+	t.Run("branches match building from scratch", func(t *testing.T) {
+		base := NewHashBuilder(0).AddString("prefix")
+
+		a := base.Clone().AddString("a").Build()
+		b := base.Clone().AddString("b").Build()
+
+		if expected := NewHashBuilder(0).AddString("prefix").AddString("a").Build(); a != expected {
+			t.Errorf("expected %+v, got %+v", expected, a)
+		}
+		if expected := NewHashBuilder(0).AddString("prefix").AddString("b").Build(); b != expected {
+			t.Errorf("expected %+v, got %+v", expected, b)
+		}
+	})
+
+	t.Run("clones are independent", func(t *testing.T) {
+		base := NewHashBuilder(0).AddString("prefix")
+		before := base.Build()
+
+		clone := base.Clone()
+		clone.AddString("suffix")
+
+		if base.Build() != before {
+			t.Error("expected changes to the clone not to affect the original")
+		}
+		base.AddNumber(1)
+		if clone.Build().StringId != "prefixsuffix" {
+			t.Errorf("expected clone StringId %q, got %q", "prefixsuffix", clone.Build().StringId)
+		}
+	})
+
+	t.Run("copies the state of a pluggable hasher", func(t *testing.T) {
+		withHashFunc := func(o *HashingOptions) { o.HashFunc = fnv.New32a }
+		base := NewHashBuilder(0).AddString("prefix", withHashFunc)
+
+		a := base.Clone().AddString("a").Build()
+		base.AddString("b")
+
+		expected := NewHashBuilder(0).AddString("prefix", withHashFunc).AddString("a").Build()
+		if a != expected {
+			t.Errorf("expected %+v, got %+v", expected, a)
+		}
+	})
+}
+
+func TestHashReader(t *testing.T) {
+	t.Run("matches HashString over the same content", func(t *testing.T) {
+		for _, content := range []string{"", "a", "hello world"} {
+			result, err := HashReader(bytes.NewReader([]byte(content)), 42)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if expected := HashString(content, 42); result.Id != expected.Id {
+				t.Errorf("%q: expected Id %d, got %d", content, expected.Id, result.Id)
+			}
+			if result.StringId != "" {
+				t.Errorf("expected empty StringId, got %q", result.StringId)
+			}
+		}
+	})
+
+	t.Run("hashes inputs delivered in many chunks", func(t *testing.T) {
+		content := strings.Repeat("0123456789", 10000)
+
+		result, err := HashReader(iotest.HalfReader(strings.NewReader(content)), 7)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if expected := HashString(content, 7); result.Id != expected.Id {
+			t.Errorf("expected Id %d, got %d", expected.Id, result.Id)
+		}
+	})
+
+	t.Run("returns the read error", func(t *testing.T) {
+		readErr := io.ErrUnexpectedEOF
+
+		result, err := HashReader(iotest.ErrReader(readErr), 0)
+		if err != readErr {
+			t.Errorf("expected %v, got %v", readErr, err)
+		}
+		if result != (HashElementId{}) {
+			t.Errorf("expected zero HashElementId, got %+v", result)
+		}
+	})
+}
+
+func TestHashNumberSet(t *testing.T) {
+	t.Run("permutations produce the same Id", func(t *testing.T) {
+		expected := HashNumberSet(7, []uint32{1, 2, 3})
+
+		for _, numbers := range [][]uint32{{3, 2, 1}, {2, 1, 3}, {1, 3, 2}} {
+			if result := HashNumberSet(7, numbers); result.Id != expected.Id {
+				t.Errorf("%v: expected Id %d, got %d", numbers, expected.Id, result.Id)
+			}
+		}
+	})
+
+	t.Run("different sets differ", func(t *testing.T) {
+		sets := [][]uint32{{}, {1}, {1, 1}, {1, 2}, {1, 2, 3}, {1, 2, 4}, {0, 3}, {2, 2}}
+		seen := map[uint32][]uint32{}
+		for _, numbers := range sets {
+			id := HashNumberSet(7, numbers).Id
+			if previous, ok := seen[id]; ok {
+				t.Errorf("expected %v and %v to differ, both got %d", previous, numbers, id)
+			}
+			seen[id] = numbers
+		}
+	})
+
+	t.Run("depends on the seed", func(t *testing.T) {
+		if HashNumberSet(1, []uint32{1, 2}).Id == HashNumberSet(2, []uint32{1, 2}).Id {
+			t.Error("expected different seeds to produce different Ids")
+		}
+	})
+}
+
+func TestHashElementId_String(t *testing.T) {
+	cases := []struct {
+		name     string
+		id       HashElementId
+		expected string
+	}{
+		{"without offset", HashElementId{Id: 1234, BaseId: 1234, StringId: "node-5"}, `HashElementId{Id: 1234, StringId: "node-5"}`},
+		{"with offset", HashElementId{Id: 1237, BaseId: 1234, Offset: 3, StringId: "item"}, `HashElementId{Id: 1237, BaseId: 1234, Offset: 3, StringId: "item"}`},
+		{"empty StringId", HashElementId{Id: 7, BaseId: 7}, `HashElementId{Id: 7, StringId: ""}`},
+		{"quotes special characters", HashElementId{Id: 1, BaseId: 1, StringId: "a\"b"}, `HashElementId{Id: 1, StringId: "a\"b"}`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.id.String(); got != c.expected {
+				t.Errorf("expected %s, got %s", c.expected, got)
+			}
+		})
+	}
+
+	t.Run("implements fmt.Stringer", func(t *testing.T) {
+		id := NewHashBuilder(0).AddString("node").Build()
+		if got := fmt.Sprint(id); got != id.String() {
+			t.Errorf("expected %s, got %s", id.String(), got)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package mem
+
+// Pool is a free list of fixed-size T slots carved from an arena. Put makes a slot available
+// to the next Get, so same-sized objects (e.g. tree nodes) can be freed and reused
+// individually, which bump allocation alone cannot do. A Pool is not safe for concurrent use.
+type Pool[T any] struct {
+	arena *Arena
+	free  []*T
+}
+
+// NewPool creates a pool over arena, pre-carving initialCount slots in a single allocation.
+func NewPool[T any](arena *Arena, initialCount int32) (*Pool[T], error) {
+	slots, err := AllocateStructArray[T](arena, initialCount)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &Pool[T]{
+		arena: arena,
+		free:  make([]*T, 0, initialCount),
+	}
+	for i := range slots {
+		pool.free = append(pool.free, &slots[i])
+	}
+	return pool, nil
+}
+
+// Get returns a zeroed slot, reusing freed slots before carving a new one from the arena.
+// It returns nil if there are no free slots and the arena is out of memory.
+func (p *Pool[T]) Get() *T {
+	if n := len(p.free); n > 0 {
+		slot := p.free[n-1]
+		p.free = p.free[:n-1]
+		var zero T
+		*slot = zero
+		return slot
+	}
+
+	slot, err := AllocateStruct[T](p.arena)
+	if err != nil {
+		return nil
+	}
+	return slot
+}
+
+// Put returns slot to the pool. slot must have come from this pool's Get and must not be
+// used afterwards; putting the same slot twice hands it out twice.
+func (p *Pool[T]) Put(slot *T) {
+	if slot == nil {
+		return
+	}
+	p.free = append(p.free, slot)
+}
+
+// Available returns the number of free slots that Get can reuse without touching the arena.
+func (p *Pool[T]) Available() int32 {
+	return int32(len(p.free))
+}
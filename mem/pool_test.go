@@ -0,0 +1,112 @@
+package mem
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	type treeNode struct {
+		Value int64
+		Left  *int64
+		Right *int64
+	}
+
+	t.Run("pre-carves initial slots", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		pool, err := NewPool[treeNode](arena, 4)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if pool.Available() != 4 {
+			t.Errorf("expected 4 available slots, got %d", pool.Available())
+		}
+
+		used := arena.NextAllocation
+		for i := 0; i < 4; i++ {
+			if pool.Get() == nil {
+				t.Fatalf("get %d: expected a slot", i)
+			}
+		}
+		if arena.NextAllocation != used {
+			t.Error("expected pre-carved slots not to touch the arena")
+		}
+	})
+
+	t.Run("reuses freed slots", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		pool, _ := NewPool[treeNode](arena, 1)
+
+		first := pool.Get()
+		first.Value = 42
+		pool.Put(first)
+		used := arena.NextAllocation
+
+		second := pool.Get()
+		if second != first {
+			t.Error("expected the freed slot to be reused")
+		}
+		if second.Value != 0 {
+			t.Errorf("expected reused slot to be zeroed, got %d", second.Value)
+		}
+		if arena.NextAllocation != used {
+			t.Error("expected reuse not to touch the arena")
+		}
+	})
+
+	t.Run("get/put cycles do not grow the arena", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		pool, _ := NewPool[treeNode](arena, 2)
+		used := arena.NextAllocation
+
+		for i := 0; i < 100; i++ {
+			a := pool.Get()
+			b := pool.Get()
+			pool.Put(a)
+			pool.Put(b)
+		}
+
+		if arena.NextAllocation != used {
+			t.Errorf("expected NextAllocation to stay at %d, got %d", used, arena.NextAllocation)
+		}
+		if pool.Available() != 2 {
+			t.Errorf("expected 2 available slots, got %d", pool.Available())
+		}
+	})
+
+	t.Run("carves from the arena when out of free slots", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		pool, _ := NewPool[treeNode](arena, 1)
+		first := pool.Get()
+		used := arena.NextAllocation
+
+		second := pool.Get()
+
+		if second == nil || second == first {
+			t.Fatal("expected a new distinct slot")
+		}
+		if arena.NextAllocation <= used {
+			t.Error("expected the arena to grow")
+		}
+	})
+
+	t.Run("returns nil when the arena is exhausted", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+		pool, _ := NewPool[treeNode](arena, 2)
+		pool.Get()
+		pool.Get()
+
+		if pool.Get() != nil {
+			t.Error("expected nil when no slots are left")
+		}
+	})
+
+	t.Run("returns error when initial slots do not fit", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := NewPool[treeNode](arena, 100); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package mem
+
+import (
+	"fmt"
+	"iter"
+)
+
+// RingBuffer is a fixed-capacity buffer carved from an arena that overwrites its oldest
+// entry once full, e.g. for streaming fixed-size records. It is not safe for concurrent use.
+type RingBuffer[T any] struct {
+	items  []T
+	start  int32
+	length int32
+}
+
+// NewRingBuffer allocates storage for capacity entries from arena. capacity must be positive.
+func NewRingBuffer[T any](arena *Arena, capacity int32) (*RingBuffer[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("ring buffer capacity must be positive, got %d", capacity)
+	}
+	items, err := AllocateStructArray[T](arena, capacity)
+	if err != nil {
+		return nil, err
+	}
+	return &RingBuffer[T]{items: items}, nil
+}
+
+// Push appends item, evicting the oldest entry when the buffer is full.
+func (r *RingBuffer[T]) Push(item T) {
+	if r.length < r.Capacity() {
+		r.items[(r.start+r.length)%r.Capacity()] = item
+		r.length++
+		return
+	}
+	r.items[r.start] = item
+	r.start = (r.start + 1) % r.Capacity()
+}
+
+// PeekOldest returns the oldest entry without removing it; ok is false when the buffer is empty.
+func (r *RingBuffer[T]) PeekOldest() (T, bool) {
+	if r.length == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.items[r.start], true
+}
+
+// Length returns the number of entries currently held.
+func (r *RingBuffer[T]) Length() int32 {
+	return r.length
+}
+
+// Capacity returns the maximum number of entries held before the oldest is overwritten.
+func (r *RingBuffer[T]) Capacity() int32 {
+	return int32(len(r.items))
+}
+
+// All returns an iterator over the entries from oldest to newest.
+func (r *RingBuffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := int32(0); i < r.length; i++ {
+			if !yield(r.items[(r.start+i)%r.Capacity()]) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package mem
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Run("keeps entries in order below capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ring, err := NewRingBuffer[int](arena, 4)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		ring.Push(1)
+		ring.Push(2)
+
+		if got := slices.Collect(ring.All()); !slices.Equal(got, []int{1, 2}) {
+			t.Errorf("expected [1 2], got %v", got)
+		}
+		if oldest, ok := ring.PeekOldest(); !ok || oldest != 1 {
+			t.Errorf("expected oldest (1, true), got (%d, %v)", oldest, ok)
+		}
+	})
+
+	t.Run("evicts the oldest entries past capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ring, _ := NewRingBuffer[int](arena, 3)
+
+		for i := 1; i <= 7; i++ {
+			ring.Push(i)
+		}
+
+		if ring.Length() != 3 || ring.Capacity() != 3 {
+			t.Errorf("expected length and capacity 3, got %d and %d", ring.Length(), ring.Capacity())
+		}
+		if got := slices.Collect(ring.All()); !slices.Equal(got, []int{5, 6, 7}) {
+			t.Errorf("expected [5 6 7], got %v", got)
+		}
+		if oldest, _ := ring.PeekOldest(); oldest != 5 {
+			t.Errorf("expected oldest 5, got %d", oldest)
+		}
+	})
+
+	t.Run("iteration can stop early", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ring, _ := NewRingBuffer[int](arena, 3)
+		for i := 1; i <= 4; i++ {
+			ring.Push(i)
+		}
+
+		var first []int
+		for v := range ring.All() {
+			first = append(first, v)
+			break
+		}
+
+		if !slices.Equal(first, []int{2}) {
+			t.Errorf("expected [2], got %v", first)
+		}
+	})
+
+	t.Run("empty buffer has no oldest entry", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		ring, _ := NewRingBuffer[int](arena, 2)
+
+		if _, ok := ring.PeekOldest(); ok {
+			t.Error("expected no oldest entry")
+		}
+		if got := slices.Collect(ring.All()); len(got) != 0 {
+			t.Errorf("expected no entries, got %v", got)
+		}
+	})
+
+	t.Run("rejects non-positive capacity", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+
+		for _, capacity := range []int32{0, -1} {
+			if _, err := NewRingBuffer[int](arena, capacity); err == nil {
+				t.Errorf("expected error for capacity %d", capacity)
+			}
+		}
+	})
+
+	t.Run("returns error when storage does not fit", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(64)
+
+		if _, err := NewRingBuffer[int64](arena, 100); !errors.Is(err, ErrCapacityExceeded) {
+			t.Errorf("expected ErrCapacityExceeded, got %v", err)
+		}
+	})
+}
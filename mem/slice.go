@@ -3,10 +3,16 @@ package mem
 import (
 	"errors"
 	"fmt"
+	"iter"
+	"slices"
+	"unsafe"
 )
 
 // ClaySlice represents the non-owning reference structure (arrayName##Slice)
 // used throughout Clay to point to a sub-range of any backing array.
+// Length and Capacity are derived from the unexported internalArray rather than stored
+// separately, so they cannot drift out of sync: Length() == len(InternalArray()) always holds
+// and every bounds check runs against it.
 type MemSlice[T any] struct {
 	internalArray []T
 }
@@ -48,6 +54,38 @@ func NewMemSliceWithData[T any](array []T) MemSlice[T] {
 	}
 }
 
+// NewMemSliceFromBytes reinterprets b, e.g. memory from Arena.AllocateBytes, as a MemSlice[T]
+// without copying: writes through the slice land in b. len(b) must be a multiple of the size
+// of T and b must start at an address aligned for T.
+func NewMemSliceFromBytes[T any](b []byte) (MemSlice[T], error) {
+	items, err := reinterpretBytes[T](b)
+	if err != nil {
+		return MemSlice[T]{}, err
+	}
+	return MemSlice[T]{internalArray: items}, nil
+}
+
+// reinterpretBytes views b as a []T without copying, checking that the length is a multiple
+// of the size of T and that b is aligned for T.
+func reinterpretBytes[T any](b []byte) ([]T, error) {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if size == 0 {
+		return nil, errors.New("cannot reinterpret bytes as a zero-size element type")
+	}
+	if uintptr(len(b))%size != 0 {
+		return nil, fmt.Errorf("byte length %d is not a multiple of the element size %d", len(b), size)
+	}
+	if len(b) == 0 {
+		return []T{}, nil
+	}
+	data := unsafe.Pointer(unsafe.SliceData(b))
+	if uintptr(data)%unsafe.Alignof(zero) != 0 {
+		return nil, fmt.Errorf("bytes are not aligned to the element alignment %d", unsafe.Alignof(zero))
+	}
+	return unsafe.Slice((*T)(data), uintptr(len(b))/size), nil
+}
+
 func MSlice_Set[T any](slice *MemSlice[T], index int32, item T) {
 	if !rangeCheck(index, slice.Length()) {
 		message := fmt.Sprintf("MemSlice.MSlice_Set index: %d, slice.Length(): %d\n", index, slice.Length())
@@ -59,6 +97,19 @@ func MSlice_Set[T any](slice *MemSlice[T], index int32, item T) {
 	internalArray[index] = item
 }
 
+// can only overwrite existing values, index < length; returns false instead of panicking
+func (s *MemSlice[T]) TrySet(index int32, item T) bool {
+	if !rangeCheck(index, s.Length()) {
+		return false
+	}
+	s.internalArray[index] = item
+	return true
+}
+
+func MSlice_TrySet[T any](slice *MemSlice[T], index int32, item T) bool {
+	return slice.TrySet(index, item)
+}
+
 func MSlice_Get[T any](slice *MemSlice[T], index int32) *T {
 	if !rangeCheck(index, slice.Length()) {
 		message := fmt.Sprintf("MemSlice.MSlice_Get index: %d, slice.Length(): %d\n", index, slice.Length())
@@ -82,6 +133,39 @@ func MSlice_GetValue[T any](slice *MemSlice[T], index int32) T {
 	return internalArray[index]
 }
 
+// read-only access that never panics: returns the zero value of T when index is out of range,
+// unlike MSlice_GetValue which panics
+func MSlice_GetValueOrZero[T any](slice *MemSlice[T], index int32) T {
+	return slice.Get(index)
+}
+
+// materializes the view into an independent MemArray with capacity == length,
+// so later changes to the base array do not affect it
+func MSlice_ToArray[T any](slice *MemSlice[T]) MemArray[T] {
+	array := MemArray[T]{
+		ZeroValuePtr:  new(T),
+		internalArray: make([]T, slice.Length()),
+	}
+	copy(array.internalArray, slice.internalArray)
+	return array
+}
+
+// alias for MSlice_ToArray, mirroring slices.Clone
+func MSlice_Clone[T any](slice *MemSlice[T]) MemArray[T] {
+	return MSlice_ToArray(slice)
+}
+
+// index of the first value equal to value within the view, index < length; -1 when not present
+func MSlice_Index[T comparable](slice *MemSlice[T], value T) int32 {
+	return int32(slices.Index(slice.internalArray, value))
+}
+
+// reports whether both views have the same length and values in [0, length), wherever they
+// start in their base arrays
+func MSlice_Equal[T comparable](a, b *MemSlice[T]) bool {
+	return slices.Equal(a.internalArray, b.internalArray)
+}
+
 func MArray_GetSlice[T any](array *MemArray[T], start int32, end int32) []T {
 	// Convert end (exclusive) to segmentLength
 	segmentLength := end - start
@@ -98,6 +182,13 @@ func MArray_GetSlice[T any](array *MemArray[T], start int32, end int32) []T {
 // It performs bounds checking and returns the non-owning ClaySlice.
 // startOffset: the starting index in the base array
 // segmentLength: the length of the slice to create (not an end index)
+// A zero segmentLength still produces a view positioned at startOffset: it is empty,
+// but growing it exposes the base array's elements from startOffset onwards.
+// The range is checked against the base array's Capacity, not its Length, so a slice can
+// cover capacity that has not been Added yet, e.g. to pre-fill a buffer. Writes through such
+// a slice land in the base array's backing storage but do not change its Length; they become
+// readable through the array once it grows over them. Use CreateSubSlice to stay within
+// populated elements.
 func CreateSliceFromRange[T any](baseArray *MemArray[T], startOffset int32, segmentLength int32) (MemSlice[T], error) {
 	if segmentLength < 0 {
 		return MemSlice[T]{}, errors.New("segmentLength cannot be negative")
@@ -120,6 +211,26 @@ func CreateSliceFromRange[T any](baseArray *MemArray[T], startOffset int32, segm
 	}, nil
 }
 
+// CreateSubSlice narrows an existing MemSlice without going back to the base array.
+// It has the same bounds-checking semantics as CreateSliceFromRange, but relative to
+// the slice's own Length. The result is still a view aliasing the same backing array.
+func CreateSubSlice[T any](slice *MemSlice[T], startOffset int32, segmentLength int32) (MemSlice[T], error) {
+	if segmentLength < 0 {
+		return MemSlice[T]{}, errors.New("segmentLength cannot be negative")
+	}
+	if startOffset < 0 {
+		return MemSlice[T]{}, errors.New("startOffset cannot be negative")
+	}
+	if segmentLength > slice.Length()-startOffset {
+		return MemSlice[T]{}, fmt.Errorf("sub-slice range exceeds the bounds of the slice: startOffset %d + segmentLength %d > length %d", startOffset, segmentLength, slice.Length())
+	}
+
+	return MemSlice[T]{
+		internalArray: slice.InternalArray()[startOffset : startOffset+segmentLength],
+	}, nil
+}
+
+// Get returns the value at index, or the zero value of T when index is out of range.
 func (slice MemSlice[T]) Get(index int32) T {
 	if !rangeCheck(index, slice.Length()) {
 		// message := fmt.Sprintf("MemSlice.Get index: %d, slice.Length: %d\n", index, slice.Length)
@@ -139,3 +250,38 @@ func MSlice_Grow[T any](slice *MemSlice[T], length int32) {
 func MSlice_Shrink[T any](slice *MemSlice[T], length int32) {
 	slice.Shrink(length)
 }
+
+// MSlice_All returns an iterator over the index and value of each element in [0, Length).
+func MSlice_All[T any](slice *MemSlice[T]) iter.Seq2[int32, T] {
+	return func(yield func(int32, T) bool) {
+		for i := int32(0); i < slice.Length(); i++ {
+			if !yield(i, slice.internalArray[i]) {
+				return
+			}
+		}
+	}
+}
+
+// MSlice_Pointers returns an iterator over the index and a pointer to each element in
+// [0, Length). The pointers point into the base array, so mutations through them are visible
+// in both the slice and the array it was created from.
+func MSlice_Pointers[T any](slice *MemSlice[T]) iter.Seq2[int32, *T] {
+	return func(yield func(int32, *T) bool) {
+		for i := int32(0); i < slice.Length(); i++ {
+			if !yield(i, &slice.internalArray[i]) {
+				return
+			}
+		}
+	}
+}
+
+// MSlice_Values returns an iterator over the values of each element in [0, Length).
+func MSlice_Values[T any](slice *MemSlice[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := int32(0); i < slice.Length(); i++ {
+			if !yield(slice.internalArray[i]) {
+				return
+			}
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package mem
 
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
 // import (
 // 	"errors"
 // 	"testing"
@@ -413,3 +419,604 @@ package mem
 // 		}
 // 	})
 // }
+
+func TestMSlice_TrySet(t *testing.T) {
+	newSlice := func() (MemArray[int], MemSlice[int]) {
+		arr := NewMemArray[int](5)
+		for i := 0; i < 5; i++ {
+			MArray_Add(&arr, i)
+		}
+		slice, err := CreateSliceFromRange(&arr, 1, 3)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return arr, slice
+	}
+
+	t.Run("sets value at valid index", func(t *testing.T) {
+		_, slice := newSlice()
+
+		if !MSlice_TrySet(&slice, 0, 100) {
+			t.Fatal("expected TrySet to succeed")
+		}
+		if MSlice_GetValue(&slice, 0) != 100 {
+			t.Errorf("expected value = 100, got %d", MSlice_GetValue(&slice, 0))
+		}
+	})
+
+	t.Run("rejects negative index", func(t *testing.T) {
+		arr, slice := newSlice()
+
+		if MSlice_TrySet(&slice, -1, 100) {
+			t.Error("expected TrySet to fail for negative index")
+		}
+		if MArray_GetValue(&arr, 0) != 0 {
+			t.Errorf("expected base array to be unchanged, got %d", MArray_GetValue(&arr, 0))
+		}
+	})
+
+	t.Run("rejects index >= length", func(t *testing.T) {
+		arr, slice := newSlice()
+
+		if MSlice_TrySet(&slice, 3, 100) {
+			t.Error("expected TrySet to fail for index >= length")
+		}
+		if MArray_GetValue(&arr, 4) != 4 {
+			t.Errorf("expected base array to be unchanged, got %d", MArray_GetValue(&arr, 4))
+		}
+	})
+
+	t.Run("write propagates to base array", func(t *testing.T) {
+		arr, slice := newSlice()
+
+		MSlice_TrySet(&slice, 2, 999)
+
+		if MArray_GetValue(&arr, 3) != 999 {
+			t.Errorf("expected base array value = 999, got %d", MArray_GetValue(&arr, 3))
+		}
+	})
+}
+
+func TestCreateSubSlice(t *testing.T) {
+	newBase := func() MemArray[int] {
+		arr := NewMemArray[int](10)
+		for i := 0; i < 10; i++ {
+			MArray_Add(&arr, i*10)
+		}
+		return arr
+	}
+
+	t.Run("creates sub-slice from valid range", func(t *testing.T) {
+		arr := newBase()
+		slice, _ := CreateSliceFromRange(&arr, 2, 6)
+
+		sub, err := CreateSubSlice(&slice, 1, 3)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if sub.Length() != 3 {
+			t.Fatalf("expected Length = 3, got %d", sub.Length())
+		}
+		for i := int32(0); i < 3; i++ {
+			expected := int(i+3) * 10
+			if MSlice_GetValue(&sub, i) != expected {
+				t.Errorf("expected sub[%d] = %d, got %d", i, expected, MSlice_GetValue(&sub, i))
+			}
+		}
+	})
+
+	t.Run("nested sub-slices alias the base array", func(t *testing.T) {
+		arr := newBase()
+		slice, _ := CreateSliceFromRange(&arr, 2, 6)
+		sub, _ := CreateSubSlice(&slice, 1, 4)
+		nested, _ := CreateSubSlice(&sub, 2, 1)
+
+		MSlice_Set(&nested, 0, 999)
+
+		// nested[0] -> sub[2] -> slice[3] -> arr[5]
+		if MArray_GetValue(&arr, 5) != 999 {
+			t.Errorf("expected arr[5] = 999, got %d", MArray_GetValue(&arr, 5))
+		}
+		if MSlice_GetValue(&sub, 2) != 999 {
+			t.Errorf("expected sub[2] = 999, got %d", MSlice_GetValue(&sub, 2))
+		}
+		if MSlice_GetValue(&slice, 3) != 999 {
+			t.Errorf("expected slice[3] = 999, got %d", MSlice_GetValue(&slice, 3))
+		}
+	})
+
+	t.Run("returns error when range exceeds slice length", func(t *testing.T) {
+		arr := newBase()
+		slice, _ := CreateSliceFromRange(&arr, 2, 4)
+
+		// The base array has room, but the slice itself only has 4 elements
+		if _, err := CreateSubSlice(&slice, 2, 3); err == nil {
+			t.Error("expected error when sub-slice exceeds slice length")
+		}
+	})
+
+	t.Run("returns error for negative arguments", func(t *testing.T) {
+		arr := newBase()
+		slice, _ := CreateSliceFromRange(&arr, 0, 4)
+
+		if _, err := CreateSubSlice(&slice, -1, 2); err == nil {
+			t.Error("expected error for negative startOffset")
+		}
+		if _, err := CreateSubSlice(&slice, 0, -1); err == nil {
+			t.Error("expected error for negative segmentLength")
+		}
+	})
+
+	t.Run("returns error instead of overflowing for huge lengths", func(t *testing.T) {
+		arr := newBase()
+		slice, _ := CreateSliceFromRange(&arr, 0, 4)
+
+		if _, err := CreateSubSlice(&slice, 1, math.MaxInt32); err == nil {
+			t.Error("expected error for startOffset + segmentLength past MaxInt32")
+		}
+		if _, err := CreateSubSlice(&slice, math.MaxInt32, 1); err == nil {
+			t.Error("expected error for startOffset past the slice")
+		}
+	})
+}
+
+func TestMSlice_All(t *testing.T) {
+	arr := NewMemArray[int](10)
+	for i := 0; i < 10; i++ {
+		MArray_Add(&arr, i*10)
+	}
+	slice, _ := CreateSliceFromRange(&arr, 3, 4)
+
+	t.Run("visits only the windowed elements in order", func(t *testing.T) {
+		expectedIndex := int32(0)
+		for i, value := range MSlice_All(&slice) {
+			if i != expectedIndex {
+				t.Errorf("expected index %d, got %d", expectedIndex, i)
+			}
+			if value != int(i+3)*10 {
+				t.Errorf("expected value %d at index %d, got %d", int(i+3)*10, i, value)
+			}
+			expectedIndex++
+		}
+		if expectedIndex != 4 {
+			t.Errorf("expected 4 iterations, got %d", expectedIndex)
+		}
+	})
+
+	t.Run("stops early on break", func(t *testing.T) {
+		count := 0
+		for range MSlice_All(&slice) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("expected 2 iterations, got %d", count)
+		}
+	})
+}
+
+func TestMSlice_Pointers(t *testing.T) {
+	type cell struct {
+		value int
+	}
+
+	t.Run("mutations propagate to the base array", func(t *testing.T) {
+		arr := NewMemArray[cell](10)
+		for i := 0; i < 10; i++ {
+			MArray_Add(&arr, cell{value: i})
+		}
+		slice, _ := CreateSliceFromRange(&arr, 3, 4)
+
+		for i, p := range MSlice_Pointers(&slice) {
+			if p.value != int(i)+3 {
+				t.Errorf("expected value %d at index %d, got %d", int(i)+3, i, p.value)
+			}
+			p.value *= 10
+		}
+
+		for i := int32(0); i < 10; i++ {
+			expected := int(i)
+			if i >= 3 && i < 7 {
+				expected *= 10
+			}
+			if got := MArray_GetValue(&arr, i).value; got != expected {
+				t.Errorf("array index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+		if got := MSlice_GetValue(&slice, 0).value; got != 30 {
+			t.Errorf("expected slice to see 30, got %d", got)
+		}
+	})
+
+	t.Run("stops early on break", func(t *testing.T) {
+		arr := NewMemArray[cell](5)
+		for i := 0; i < 5; i++ {
+			MArray_Add(&arr, cell{})
+		}
+		slice, _ := CreateSliceFromRange(&arr, 0, 5)
+
+		count := 0
+		for range MSlice_Pointers(&slice) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("expected 2 iterations, got %d", count)
+		}
+	})
+}
+
+func TestMSlice_Values(t *testing.T) {
+	arr := NewMemArray[int](10)
+	for i := 0; i < 10; i++ {
+		MArray_Add(&arr, i*10)
+	}
+	slice, _ := CreateSliceFromRange(&arr, 3, 4)
+
+	t.Run("visits only the windowed values in order", func(t *testing.T) {
+		var values []int
+		for value := range MSlice_Values(&slice) {
+			values = append(values, value)
+		}
+
+		expected := []int{30, 40, 50, 60}
+		if len(values) != len(expected) {
+			t.Fatalf("expected %d values, got %d", len(expected), len(values))
+		}
+		for i := range expected {
+			if values[i] != expected[i] {
+				t.Errorf("expected values[%d] = %d, got %d", i, expected[i], values[i])
+			}
+		}
+	})
+}
+
+func TestCreateSliceFromRange_ZeroLength(t *testing.T) {
+	t.Run("zero-length slice is a view at startOffset", func(t *testing.T) {
+		arr := NewMemArray[int](10)
+		for i := 0; i < 3; i++ {
+			MArray_Add(&arr, i)
+		}
+
+		slice, err := CreateSliceFromRange(&arr, 3, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if slice.Length() != 0 {
+			t.Fatalf("expected Length = 0, got %d", slice.Length())
+		}
+
+		// Append into the base array at the slice's offset
+		MArray_Add(&arr, 30)
+		MArray_Add(&arr, 40)
+
+		if slice.Length() != 0 {
+			t.Errorf("expected slice to remain empty, got Length = %d", slice.Length())
+		}
+
+		// Growing the empty view exposes the base elements from startOffset
+		MSlice_Grow(&slice, 2)
+		if MSlice_GetValue(&slice, 0) != 30 || MSlice_GetValue(&slice, 1) != 40 {
+			t.Errorf("expected [30 40], got %v", slice.InternalArray())
+		}
+		MSlice_Set(&slice, 0, 300)
+		if MArray_GetValue(&arr, 3) != 300 {
+			t.Errorf("expected arr[3] = 300, got %d", MArray_GetValue(&arr, 3))
+		}
+	})
+
+	t.Run("zero-length slice at capacity is valid", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		slice, err := CreateSliceFromRange(&arr, 4, 0)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if slice.Length() != 0 || slice.Capacity() != 0 {
+			t.Errorf("expected Length = 0 and Capacity = 0, got %d and %d", slice.Length(), slice.Capacity())
+		}
+	})
+}
+
+func TestMSlice_GetValueOrZero(t *testing.T) {
+	t.Run("returns the value at valid indices", func(t *testing.T) {
+		slice := NewMemSliceWithData([]int{10, 20, 30})
+
+		for i, expected := range []int{10, 20, 30} {
+			if got := MSlice_GetValueOrZero(&slice, int32(i)); got != expected {
+				t.Errorf("index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("returns the zero value for out of range indices", func(t *testing.T) {
+		type point struct {
+			X, Y int
+		}
+		slice := NewMemSliceWithData([]point{{X: 1, Y: 2}})
+
+		for _, index := range []int32{-1, 1, 100} {
+			if got := MSlice_GetValueOrZero(&slice, index); got != (point{}) {
+				t.Errorf("index %d: expected zero value, got %+v", index, got)
+			}
+		}
+	})
+
+	t.Run("does not read the capacity tail", func(t *testing.T) {
+		backing := []int{1, 2, 3}
+		slice := NewMemSliceWithData(backing[:1])
+
+		if got := MSlice_GetValueOrZero(&slice, 1); got != 0 {
+			t.Errorf("expected zero value beyond Length, got %d", got)
+		}
+	})
+}
+
+func TestMSlice_ToArray(t *testing.T) {
+	t.Run("materialized array is independent of the base array", func(t *testing.T) {
+		arr := NewMemArray[int](5)
+		for i := 1; i <= 5; i++ {
+			MArray_Add(&arr, i*10)
+		}
+		slice, _ := CreateSliceFromRange(&arr, 1, 3)
+
+		materialized := MSlice_ToArray(&slice)
+		MArray_Set(&arr, 1, 999)
+		MSlice_Set(&slice, 2, 888)
+
+		if materialized.Length() != 3 || materialized.Capacity() != 3 {
+			t.Fatalf("expected length and capacity 3, got %d and %d", materialized.Length(), materialized.Capacity())
+		}
+		for i, expected := range []int{20, 30, 40} {
+			if got := MArray_GetValue(&materialized, int32(i)); got != expected {
+				t.Errorf("index %d: expected %d, got %d", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("empty slice materializes to an empty array", func(t *testing.T) {
+		slice := NewMemSlice[int](0)
+
+		materialized := MSlice_ToArray(&slice)
+
+		if materialized.Length() != 0 || materialized.Capacity() != 0 {
+			t.Errorf("expected empty array, got length %d and capacity %d", materialized.Length(), materialized.Capacity())
+		}
+	})
+}
+
+func TestNewMemSliceFromBytes(t *testing.T) {
+	t.Run("reinterprets arena bytes without copying", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		b, err := arena.AllocateBytes(16)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		slice, err := NewMemSliceFromBytes[int32](b)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if slice.Length() != 4 {
+			t.Fatalf("expected length 4, got %d", slice.Length())
+		}
+
+		MSlice_Set(&slice, 0, 1)
+		MSlice_Set(&slice, 3, -2)
+
+		if binary.NativeEndian.Uint32(b[0:4]) != 1 {
+			t.Errorf("expected write at index 0 to land in the bytes, got %v", b[0:4])
+		}
+		if int32(binary.NativeEndian.Uint32(b[12:16])) != -2 {
+			t.Errorf("expected write at index 3 to land in the bytes, got %v", b[12:16])
+		}
+	})
+
+	t.Run("empty bytes produce an empty slice", func(t *testing.T) {
+		slice, err := NewMemSliceFromBytes[int32](nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if slice.Length() != 0 {
+			t.Errorf("expected length 0, got %d", slice.Length())
+		}
+	})
+
+	t.Run("rejects partial element sizes", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		b, _ := arena.AllocateBytes(10)
+
+		if _, err := NewMemSliceFromBytes[int32](b); err == nil {
+			t.Error("expected error for a length that is not a multiple of 4")
+		}
+	})
+
+	t.Run("rejects misaligned bytes", func(t *testing.T) {
+		arena := NewArenaWithSizeUnsafe(1024)
+		b, _ := arena.AllocateBytes(17)
+
+		if _, err := NewMemSliceFromBytes[int32](b[1:]); err == nil {
+			t.Error("expected error for misaligned bytes")
+		}
+	})
+
+	t.Run("rejects zero-size element types", func(t *testing.T) {
+		if _, err := NewMemSliceFromBytes[struct{}](make([]byte, 4)); err == nil {
+			t.Error("expected error for a zero-size element type")
+		}
+	})
+}
+
+func TestMemSlice_LengthInvariant(t *testing.T) {
+	t.Run("length always matches the backing slice", func(t *testing.T) {
+		arr := NewMemArray[int](8)
+		for i := 0; i < 4; i++ {
+			MArray_Add(&arr, i)
+		}
+		slice, _ := CreateSliceFromRange(&arr, 1, 2)
+
+		check := func(step string) {
+			t.Helper()
+			if int(slice.Length()) != len(slice.InternalArray()) {
+				t.Errorf("%s: Length %d != len(InternalArray) %d", step, slice.Length(), len(slice.InternalArray()))
+			}
+			if int(slice.Capacity()) != cap(slice.InternalArray()) {
+				t.Errorf("%s: Capacity %d != cap(InternalArray) %d", step, slice.Capacity(), cap(slice.InternalArray()))
+			}
+		}
+
+		check("create")
+		MSlice_Grow(&slice, 3)
+		check("grow")
+		MSlice_Shrink(&slice, 4)
+		check("shrink")
+	})
+
+	t.Run("bounds checks follow Length after shrinking", func(t *testing.T) {
+		slice := NewMemSliceWithData([]int{1, 2, 3})
+		MSlice_Shrink(&slice, 1)
+
+		if MSlice_TrySet(&slice, 2, 9) {
+			t.Error("expected index 2 to be out of range after shrinking to length 2")
+		}
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected MSlice_Get to panic beyond Length")
+			}
+		}()
+		MSlice_Get(&slice, 2)
+	})
+}
+
+func TestCreateSliceFromRange_UnpopulatedCapacity(t *testing.T) {
+	t.Run("slices over capacity that has not been added", func(t *testing.T) {
+		arr := NewMemArray[int](8)
+		MArray_Add(&arr, 1)
+
+		slice, err := CreateSliceFromRange(&arr, 2, 4)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		for i := int32(0); i < slice.Length(); i++ {
+			MSlice_Set(&slice, i, int(i)*10)
+		}
+
+		if arr.Length() != 1 {
+			t.Errorf("expected base Length to stay 1, got %d", arr.Length())
+		}
+		MArray_Grow(&arr, 5)
+		for i := int32(0); i < 4; i++ {
+			if got := MArray_GetValue(&arr, 2+i); got != int(i)*10 {
+				t.Errorf("base index %d: expected %d, got %d", 2+i, i*10, got)
+			}
+		}
+	})
+
+	t.Run("pre-filled values become visible when the array grows", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+		slice, _ := CreateSliceFromRange(&arr, 0, 4)
+		for i := int32(0); i < 4; i++ {
+			MSlice_Set(&slice, i, int(i)+1)
+		}
+
+		MArray_Grow(&arr, 4)
+
+		for i := int32(0); i < 4; i++ {
+			if got := MArray_GetValue(&arr, i); got != int(i)+1 {
+				t.Errorf("index %d: expected %d, got %d", i, i+1, got)
+			}
+		}
+	})
+
+	t.Run("rejects ranges beyond capacity", func(t *testing.T) {
+		arr := NewMemArray[int](4)
+
+		if _, err := CreateSliceFromRange(&arr, 2, 3); err == nil {
+			t.Error("expected error for a range past capacity")
+		}
+	})
+}
+
+func TestMSlice_Clone(t *testing.T) {
+	arr := NewMemArray[int](10)
+	for i := 0; i < 10; i++ {
+		MArray_Add(&arr, i)
+	}
+	slice, _ := CreateSliceFromRange(&arr, 2, 3)
+
+	t.Run("materializes the window into an independent array", func(t *testing.T) {
+		clone := MSlice_Clone(&slice)
+
+		if clone.Length() != 3 || clone.Capacity() != 3 {
+			t.Errorf("expected length and capacity 3, got %d and %d", clone.Length(), clone.Capacity())
+		}
+		for i := int32(0); i < 3; i++ {
+			if MArray_GetValue(&clone, i) != int(i)+2 {
+				t.Errorf("expected %d at index %d, got %d", i+2, i, MArray_GetValue(&clone, i))
+			}
+		}
+
+		MArray_Set(&arr, 2, 100)
+		if MArray_GetValue(&clone, 0) != 2 {
+			t.Error("expected the clone to be unaffected by changes to the base array")
+		}
+	})
+}
+
+func TestMSlice_Index(t *testing.T) {
+	arr := NewMemArray[int](10)
+	for _, value := range []int{5, 6, 7, 6, 9, 4} {
+		MArray_Add(&arr, value)
+	}
+	slice, _ := CreateSliceFromRange(&arr, 1, 3)
+
+	t.Run("returns the first index within the window", func(t *testing.T) {
+		if index := MSlice_Index(&slice, 6); index != 0 {
+			t.Errorf("expected 0, got %d", index)
+		}
+		if index := MSlice_Index(&slice, 7); index != 1 {
+			t.Errorf("expected 1, got %d", index)
+		}
+	})
+
+	t.Run("ignores values outside the window", func(t *testing.T) {
+		for _, value := range []int{5, 9, 4, 42} {
+			if index := MSlice_Index(&slice, value); index != -1 {
+				t.Errorf("value %d: expected -1, got %d", value, index)
+			}
+		}
+	})
+}
+
+func TestMSlice_Equal(t *testing.T) {
+	arr := NewMemArray[int](10)
+	for _, value := range []int{1, 2, 3, 1, 2, 3, 4} {
+		MArray_Add(&arr, value)
+	}
+
+	t.Run("compares windowed contents regardless of position", func(t *testing.T) {
+		a, _ := CreateSliceFromRange(&arr, 0, 3)
+		b, _ := CreateSliceFromRange(&arr, 3, 3)
+
+		if !MSlice_Equal(&a, &b) {
+			t.Error("expected equal windows to compare equal")
+		}
+	})
+
+	t.Run("different values or lengths differ", func(t *testing.T) {
+		a, _ := CreateSliceFromRange(&arr, 0, 3)
+		shifted, _ := CreateSliceFromRange(&arr, 1, 3)
+		longer, _ := CreateSliceFromRange(&arr, 3, 4)
+
+		if MSlice_Equal(&a, &shifted) {
+			t.Error("expected windows with different values to differ")
+		}
+		if MSlice_Equal(&a, &longer) {
+			t.Error("expected windows of different lengths to differ")
+		}
+	})
+}